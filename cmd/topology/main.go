@@ -18,8 +18,10 @@ package main
 
 import (
 	"context"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dell/karavi-topology/internal/entrypoint"
 	"github.com/dell/karavi-topology/internal/k8s"
@@ -27,6 +29,7 @@ import (
 	tracer "github.com/dell/karavi-topology/internal/tracers"
 	"github.com/fsnotify/fsnotify"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel"
 )
@@ -35,6 +38,9 @@ const (
 	defaultConfigFile = "/etc/config/karavi-topology.yaml"
 	defaultCertFile   = "/certs/localhost.crt"
 	defaultKeyFile    = "/certs/localhost.key"
+
+	defaultHistoryRetention = 24 * time.Hour
+	defaultHistoryMaxEvents = 10000
 )
 
 type ServiceConfig struct {
@@ -43,12 +49,32 @@ type ServiceConfig struct {
 	Port         int
 	EnableDebug  bool
 	VolumeFinder *k8s.VolumeFinder
+	// ClusterFinder aggregates the clusters of CLUSTERS; nil when a single cluster is watched through VolumeFinder
+	ClusterFinder *k8s.ClusterFinder
+	VolumeHistory *k8s.VolumeHistory
 }
 
 func main() {
+	if err := bindFlags(pflag.CommandLine, os.Args[1:]); err != nil {
+		logrus.WithError(err).Fatal("Parsing flags failed")
+	}
 	mainWithEntrypoint(entrypoint.Run)
 }
 
+// bindFlags parses the command line flags and binds them to their viper keys, so a flag given on the command line
+// takes precedence over the environment and the config file
+func bindFlags(flags *pflag.FlagSet, args []string) error {
+	flags.String("kubeconfig", "", "path of the kubeconfig file to connect with; the in-cluster configuration is used when empty")
+	flags.String("context", "", "kubeconfig context to connect to; the current context is used when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := viper.BindPFlag("KUBECONFIG", flags.Lookup("kubeconfig")); err != nil {
+		return err
+	}
+	return viper.BindPFlag("KUBE_CONTEXT", flags.Lookup("context"))
+}
+
 func mainWithEntrypoint(entrypointRun func(ctx context.Context, service entrypoint.ServiceRunner) error) {
 	logger := configureLogger()
 	setupViper(logger)
@@ -56,7 +82,10 @@ func mainWithEntrypoint(entrypointRun func(ctx context.Context, service entrypoi
 	setupConfigWatchers(logger, config)
 	initializeTracing(logger)
 
-	if err := entrypointRun(context.Background(), createService(config, logger)); err != nil {
+	ctx := context.Background()
+	startInformers(ctx, logger, config)
+
+	if err := entrypointRun(ctx, createService(config, logger)); err != nil {
 		logger.WithError(err).Fatal("Service startup failed")
 	}
 }
@@ -90,31 +119,216 @@ func setupViper(logger *logrus.Logger) {
 }
 
 func initializeServiceConfig(logger *logrus.Logger) *ServiceConfig {
-	return &ServiceConfig{
+	config := &ServiceConfig{
 		CertFile:     getEnvWithDefault("TLS_CERT_PATH", defaultCertFile),
 		KeyFile:      getEnvWithDefault("TLS_KEY_PATH", defaultKeyFile),
 		Port:         parsePort(logger),
 		EnableDebug:  parseDebugFlag(logger),
 		VolumeFinder: createVolumeFinder(logger),
 	}
+	config.ClusterFinder = createClusterFinder(logger, config.VolumeFinder)
+	config.VolumeHistory = createVolumeHistory(logger, config)
+	return config
+}
+
+// createVolumeHistory returns the change history and registers it with the persistent volume informer of every cluster
+func createVolumeHistory(logger *logrus.Logger, config *ServiceConfig) *k8s.VolumeHistory {
+	history := k8s.NewVolumeHistory(parseHistoryRetention(logger))
+	for _, finder := range volumeFinders(config) {
+		if api, ok := finder.API.(*k8s.API); ok {
+			api.VolumeEventHandler = finder.VolumeEventHandler(history)
+		}
+	}
+	return history
+}
+
+// parseHistoryRetention returns the retention window and the maximum number of events of the change history from
+// CHANGE_HISTORY_RETENTION and CHANGE_HISTORY_MAX_EVENTS, using the defaults for unset or invalid values
+func parseHistoryRetention(logger *logrus.Logger) (time.Duration, int) {
+	retention := defaultHistoryRetention
+	if value := strings.TrimSpace(viper.GetString("CHANGE_HISTORY_RETENTION")); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+			retention = d
+		} else {
+			logger.WithField("value", value).Warnf("Invalid CHANGE_HISTORY_RETENTION; using %s", defaultHistoryRetention)
+		}
+	}
+
+	maxEvents := defaultHistoryMaxEvents
+	if value := strings.TrimSpace(viper.GetString("CHANGE_HISTORY_MAX_EVENTS")); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			maxEvents = n
+		} else {
+			logger.WithField("value", value).Warnf("Invalid CHANGE_HISTORY_MAX_EVENTS; using %d", defaultHistoryMaxEvents)
+		}
+	}
+	return retention, maxEvents
 }
 
 func createVolumeFinder(logger *logrus.Logger) *k8s.VolumeFinder {
 	vf := &k8s.VolumeFinder{
-		API:    &k8s.API{},
-		Logger: logger,
+		API:     createAPI(logger),
+		Logger:  logger,
+		Cluster: strings.TrimSpace(viper.GetString("CLUSTER_NAME")),
+	}
+	settings := k8s.DriverSettings{
+		DriverNames: parseDriverNames(logger),
+		Extractors:  k8s.NewExtractorRegistry(),
 	}
-	vf.DriverNames = parseDriverNames(logger)
+	updateDriverMatchers(logger, &settings)
+	updateAttributeMappings(logger, &settings)
+	vf.SetSettings(settings)
 	return vf
 }
 
+// createAPI returns the Kubernetes API client, connecting through the KUBECONFIG file and KUBE_CONTEXT context when
+// they are set and with the in-cluster configuration otherwise
+func createAPI(logger *logrus.Logger) *k8s.API {
+	api := &k8s.API{
+		Kubeconfig: strings.TrimSpace(viper.GetString("KUBECONFIG")),
+		Context:    strings.TrimSpace(viper.GetString("KUBE_CONTEXT")),
+	}
+	if api.Kubeconfig != "" || api.Context != "" {
+		logger.WithFields(logrus.Fields{
+			"kubeconfig": api.Kubeconfig,
+			"context":    api.Context,
+		}).Info("Connecting to Kubernetes with kubeconfig")
+	}
+	return api
+}
+
+// createClusterFinder returns a cluster finder with a volume finder for every valid cluster of CLUSTERS, or nil when
+// no cluster is defined. The cluster finders share the provisioner and attribute settings of vf.
+func createClusterFinder(logger *logrus.Logger, vf *k8s.VolumeFinder) *k8s.ClusterFinder {
+	var clusters []k8s.ClusterConfig
+	if err := viper.UnmarshalKey("CLUSTERS", &clusters); err != nil {
+		logger.WithError(err).Error("Invalid CLUSTERS; watching a single cluster")
+		return nil
+	}
+
+	finder := &k8s.ClusterFinder{Logger: logger}
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		if err := cluster.Validate(); err != nil {
+			logger.WithError(err).Error("Invalid cluster in CLUSTERS; skipping it")
+			continue
+		}
+		if k8s.Contains(names, cluster.Name) {
+			logger.WithField("cluster", cluster.Name).Error("Duplicate cluster in CLUSTERS; skipping it")
+			continue
+		}
+		names = append(names, cluster.Name)
+		finder.Finders = append(finder.Finders, &k8s.VolumeFinder{
+			API:     cluster.NewAPI(),
+			Logger:  logger,
+			Cluster: cluster.Name,
+		})
+	}
+	if len(finder.Finders) == 0 {
+		if len(clusters) > 0 {
+			logger.Error("No valid cluster in CLUSTERS; watching a single cluster")
+		}
+		return nil
+	}
+	updateClusterFinders(finder, vf.Settings())
+	logger.WithField("clusters", strings.Join(names, ",")).Info("Aggregating the topology of several clusters")
+	return finder
+}
+
+// updateClusterFinders publishes the driver settings to the volume finder of every cluster
+func updateClusterFinders(finder *k8s.ClusterFinder, settings k8s.DriverSettings) {
+	for _, f := range finder.Finders {
+		f.SetSettings(settings)
+	}
+}
+
+// volumeFinders returns the volume finder of every cluster
+func volumeFinders(config *ServiceConfig) []*k8s.VolumeFinder {
+	if config.ClusterFinder != nil {
+		return config.ClusterFinder.Finders
+	}
+	return []*k8s.VolumeFinder{config.VolumeFinder}
+}
+
+func startInformers(ctx context.Context, logger *logrus.Logger, config *ServiceConfig) {
+	for _, finder := range volumeFinders(config) {
+		api, ok := finder.API.(*k8s.API)
+		if !ok {
+			continue
+		}
+		api.WatchCSIDrivers = finder.DiscoversDrivers()
+		if err := api.Start(ctx); err != nil {
+			logger.WithError(err).WithField("cluster", finder.Cluster).Error("Starting Kubernetes informers failed; retrying on first request")
+		}
+	}
+}
+
 func parseDriverNames(logger *logrus.Logger) []string {
 	names := strings.TrimSpace(viper.GetString("PROVISIONER_NAMES"))
 	if names == "" {
-		logger.Warn("PROVISIONER_NAMES is empty; no provisioners will be used")
+		logger.Warn("PROVISIONER_NAMES is empty; provisioners will be discovered from CSIDriver objects")
 		return nil
 	}
-	return strings.Split(names, ",")
+	return k8s.SplitDriverPatterns(names)
+}
+
+// updateDriverMatchers compiles the PROVISIONER_NAMES patterns and, when they only hold exclusions or nothing, the
+// patterns used to discover the installed drivers. Invalid patterns are logged and the previous matchers are kept.
+func updateDriverMatchers(logger *logrus.Logger, settings *k8s.DriverSettings) {
+	matcher, err := k8s.NewDriverMatcher(settings.DriverNames)
+	if err != nil {
+		logger.WithError(err).Error("Invalid PROVISIONER_NAMES; keeping the previous provisioners")
+		return
+	}
+	settings.DriverMatcher = matcher
+	if !matcher.Empty() {
+		settings.Discovery = nil
+		return
+	}
+	updateDriverDiscovery(logger, settings, matcher.Exclusions())
+}
+
+// updateDriverDiscovery matches the installed CSI drivers against DRIVER_DISCOVERY_PATTERNS or, when unset, invalid
+// or only made of exclusions, the Dell driver patterns. The exclusions of PROVISIONER_NAMES also apply.
+func updateDriverDiscovery(logger *logrus.Logger, settings *k8s.DriverSettings, exclusions []string) {
+	var patterns []string
+	if configured := strings.TrimSpace(viper.GetString("DRIVER_DISCOVERY_PATTERNS")); configured != "" {
+		patterns = k8s.SplitDriverPatterns(configured)
+	}
+	discovery, err := k8s.NewDriverMatcher(append(patterns, exclusions...))
+	if err != nil {
+		logger.WithError(err).Error("Invalid DRIVER_DISCOVERY_PATTERNS; using the Dell driver patterns")
+		discovery, _ = k8s.NewDriverMatcher(exclusions)
+	}
+	if discovery.Empty() {
+		discovery, _ = k8s.NewDriverMatcher(append(append([]string{}, k8s.DellDriverPatterns...), discovery.Exclusions()...))
+	}
+	settings.Discovery = discovery
+	logger.WithField("patterns", discovery.String()).Info("Discovering drivers from CSIDriver objects")
+}
+
+// updateAttributeMappings sets the driver settings to a new extractor registry with the DRIVER_ATTRIBUTE_MAPPINGS of the
+// config file, keeping the previous registry when they are invalid. The previous registry is not changed, since it may
+// be in use until the new settings are published.
+func updateAttributeMappings(logger *logrus.Logger, settings *k8s.DriverSettings) {
+	if settings.Extractors == nil {
+		settings.Extractors = k8s.NewExtractorRegistry()
+	}
+
+	var mappings []k8s.AttributeMapping
+	if err := viper.UnmarshalKey("DRIVER_ATTRIBUTE_MAPPINGS", &mappings); err != nil {
+		logger.WithError(err).Error("Invalid DRIVER_ATTRIBUTE_MAPPINGS; keeping the previous mappings")
+		return
+	}
+	extractors, err := settings.Extractors.WithMappings(mappings)
+	if err != nil {
+		logger.WithError(err).Error("Invalid DRIVER_ATTRIBUTE_MAPPINGS; keeping the previous mappings")
+		return
+	}
+	settings.Extractors = extractors
+	if len(mappings) > 0 {
+		logger.WithField("mappings", len(mappings)).Info("Configured driver attribute mappings")
+	}
 }
 
 func setupConfigWatchers(logger *logrus.Logger, config *ServiceConfig) {
@@ -127,7 +341,17 @@ func setupConfigWatchers(logger *logrus.Logger, config *ServiceConfig) {
 func handleConfigChange(e fsnotify.Event, logger *logrus.Logger, config *ServiceConfig) {
 	logger.WithField("file", e.Name).Info("Configuration updated")
 	updateLogSettings(logger)
-	config.VolumeFinder.DriverNames = parseDriverNames(logger)
+	settings := config.VolumeFinder.Settings()
+	settings.DriverNames = parseDriverNames(logger)
+	updateDriverMatchers(logger, &settings)
+	updateAttributeMappings(logger, &settings)
+	config.VolumeFinder.SetSettings(settings)
+	if config.ClusterFinder != nil {
+		updateClusterFinders(config.ClusterFinder, settings)
+	}
+	if config.VolumeHistory != nil {
+		config.VolumeHistory.SetRetention(parseHistoryRetention(logger))
+	}
 	initializeTracing(logger)
 }
 
@@ -157,14 +381,22 @@ func initializeTracing(logger *logrus.Logger) {
 }
 
 func createService(config *ServiceConfig, logger *logrus.Logger) *service.Service {
-	return &service.Service{
-		VolumeFinder: config.VolumeFinder,
+	var volumeFinder service.VolumeInfoGetter = config.VolumeFinder
+	if config.ClusterFinder != nil {
+		volumeFinder = config.ClusterFinder
+	}
+	svc := &service.Service{
+		VolumeFinder: volumeFinder,
 		CertFile:     config.CertFile,
 		KeyFile:      config.KeyFile,
 		Port:         config.Port,
 		Logger:       logger,
 		EnableDebug:  config.EnableDebug,
 	}
+	if config.VolumeHistory != nil {
+		svc.VolumeHistory = config.VolumeHistory
+	}
+	return svc
 }
 
 func getEnvWithDefault(envVar, defaultValue string) string {
@@ -20,15 +20,19 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dell/karavi-topology/internal/entrypoint"
 	"github.com/dell/karavi-topology/internal/k8s"
 	"github.com/dell/karavi-topology/internal/service"
 	"github.com/fsnotify/fsnotify"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestMainFunction(t *testing.T) {
@@ -82,6 +86,40 @@ func TestMainFunction(t *testing.T) {
 	}
 }
 
+func TestBindFlags(t *testing.T) {
+	defer viper.Reset()
+	logger := logrus.New()
+
+	viper.Reset()
+	t.Setenv("KUBECONFIG", "/env/kubeconfig")
+	viper.AutomaticEnv()
+	flags := pflag.NewFlagSet("topology", pflag.ContinueOnError)
+	assert.NoError(t, bindFlags(flags, []string{"--context", "remote"}))
+	api := createAPI(logger)
+	assert.Equal(t, "/env/kubeconfig", api.Kubeconfig)
+	assert.Equal(t, "remote", api.Context)
+
+	viper.Reset()
+	t.Setenv("KUBECONFIG", "/env/kubeconfig")
+	viper.AutomaticEnv()
+	flags = pflag.NewFlagSet("topology", pflag.ContinueOnError)
+	assert.NoError(t, bindFlags(flags, []string{"--kubeconfig=/flag/kubeconfig"}))
+	api = createAPI(logger)
+	assert.Equal(t, "/flag/kubeconfig", api.Kubeconfig)
+	assert.Empty(t, api.Context)
+
+	flags = pflag.NewFlagSet("topology", pflag.ContinueOnError)
+	assert.Error(t, bindFlags(flags, []string{"--unknown"}))
+}
+
+func TestCreateAPIInCluster(t *testing.T) {
+	viper.Set("KUBECONFIG", "")
+	viper.Set("KUBE_CONTEXT", "")
+	api := createAPI(logrus.New())
+	assert.Empty(t, api.Kubeconfig)
+	assert.Empty(t, api.Context)
+}
+
 func TestConfigureLogger(t *testing.T) {
 	logger := configureLogger()
 	assert.NotNil(t, logger)
@@ -109,7 +147,7 @@ func TestInitializeServiceConfig(t *testing.T) {
 	assert.Equal(t, 9090, config.Port)
 	assert.True(t, config.EnableDebug)
 	assert.NotNil(t, config.VolumeFinder)
-	assert.Equal(t, []string{"driver1", "driver2"}, config.VolumeFinder.DriverNames)
+	assert.Equal(t, []string{"driver1", "driver2"}, config.VolumeFinder.Settings().DriverNames)
 }
 
 func TestCreateVolumeFinder(t *testing.T) {
@@ -118,7 +156,8 @@ func TestCreateVolumeFinder(t *testing.T) {
 
 	vf := createVolumeFinder(logger)
 	assert.NotNil(t, vf)
-	assert.Equal(t, []string{"driver1", "driver2"}, vf.DriverNames)
+	assert.Equal(t, []string{"driver1", "driver2"}, vf.Settings().DriverNames)
+	assert.NotNil(t, vf.Settings().Extractors)
 	assert.IsType(t, &k8s.API{}, vf.API)
 }
 
@@ -130,6 +169,7 @@ func TestParseDriverNames(t *testing.T) {
 	}{
 		{"Valid drivers", "driver1,driver2", []string{"driver1", "driver2"}},
 		{"Single driver", "driver1", []string{"driver1"}},
+		{"Regular expression with commas", "/csi-[a-z]{1,2}\\.dellemc\\.com/,driver2", []string{"/csi-[a-z]{1,2}\\.dellemc\\.com/", "driver2"}},
 		{"Empty input", "", nil},
 		{"Whitespace input", "  ", nil},
 	}
@@ -144,6 +184,52 @@ func TestParseDriverNames(t *testing.T) {
 	}
 }
 
+func TestUpdateDriverMatchers(t *testing.T) {
+	tests := []struct {
+		name              string
+		driverNames       []string
+		patterns          string
+		expectedMatcher   string
+		expectedDiscovery string
+	}{
+		{"Provisioner names disable discovery", []string{"driver1"}, "", "driver1", ""},
+		{"Provisioner patterns", []string{"csi-*.dellemc.com", "!csi-unity*"}, "", "csi-*.dellemc.com,!csi-unity*", ""},
+		{"Dell driver patterns", nil, "", "", strings.Join(k8s.DellDriverPatterns, ",")},
+		{"Configured patterns", nil, "csi-unity*.dellemc.com,/csi-isilon-.*/", "", "csi-unity*.dellemc.com,/csi-isilon-.*/"},
+		{"Invalid patterns", nil, "csi-[", "", strings.Join(k8s.DellDriverPatterns, ",")},
+		{"Patterns with commas", nil, "/csi-[a-z]{1,2}/,csi-unity*", "", "/csi-[a-z]{1,2}/,csi-unity*"},
+		{"Provisioner exclusions apply to discovery", []string{"!csi-unity*"}, "csi-*", "!csi-unity*", "csi-*,!csi-unity*"},
+		{"Discovery exclusions extend the Dell patterns", nil, "!csi-unity*", "", strings.Join(k8s.DellDriverPatterns, ",") + ",!csi-unity*"},
+	}
+
+	logger := logrus.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("DRIVER_DISCOVERY_PATTERNS", tt.patterns)
+			settings := &k8s.DriverSettings{DriverNames: tt.driverNames}
+			updateDriverMatchers(logger, settings)
+
+			assert.Equal(t, tt.expectedMatcher, settings.DriverMatcher.String())
+			if tt.expectedDiscovery == "" {
+				assert.Nil(t, settings.Discovery)
+			} else {
+				assert.Equal(t, tt.expectedDiscovery, settings.Discovery.String())
+			}
+		})
+	}
+	viper.Set("DRIVER_DISCOVERY_PATTERNS", "")
+}
+
+func TestUpdateDriverMatchersKeepsPreviousOnError(t *testing.T) {
+	logger := logrus.New()
+	settings := &k8s.DriverSettings{DriverNames: []string{"csi-*.dellemc.com"}}
+	updateDriverMatchers(logger, settings)
+
+	settings.DriverNames = []string{"csi-["}
+	updateDriverMatchers(logger, settings)
+	assert.Equal(t, "csi-*.dellemc.com", settings.DriverMatcher.String())
+}
+
 func TestHandleConfigChange(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -189,8 +275,8 @@ func TestHandleConfigChange(t *testing.T) {
 			handleConfigChange(tt.event, logger, config)
 
 			// Validate changes
-			if len(config.VolumeFinder.DriverNames) != len(tt.expectedDriverNames) {
-				t.Errorf("Expected driver names %v, got %v", tt.expectedDriverNames, config.VolumeFinder.DriverNames)
+			if driverNames := config.VolumeFinder.Settings().DriverNames; len(driverNames) != len(tt.expectedDriverNames) {
+				t.Errorf("Expected driver names %v, got %v", tt.expectedDriverNames, driverNames)
 			}
 		})
 	}
@@ -286,3 +372,201 @@ func TestParseDebugFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateAttributeMappings(t *testing.T) {
+	logger := logrus.New()
+	viper.SetConfigType("yaml")
+	defer viper.Reset()
+
+	assert.Nil(t, viper.ReadConfig(bytes.NewBufferString(`
+DRIVER_ATTRIBUTE_MAPPINGS:
+  - driver: "csi-vxflexos-*.dellemc.com"
+    storageSystem:
+      attribute: SystemID
+  - driver: block.example.com
+    handleSeparator: "/"
+    volumeName:
+      handleSegment: -1
+    protocol:
+      value: iscsi
+`)))
+	settings := &k8s.DriverSettings{}
+	updateAttributeMappings(logger, settings)
+	assert.NotNil(t, settings.Extractors)
+
+	volume := &corev1.PersistentVolume{Spec: corev1.PersistentVolumeSpec{
+		PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{
+			Driver:           "block.example.com",
+			VolumeHandle:     "array-1/vol-1",
+			VolumeAttributes: map[string]string{"SystemID": "system-1"},
+		}},
+	}}
+	attributes := settings.Extractors.Lookup("block.example.com").Extract(volume)
+	assert.Equal(t, "vol-1", attributes.StorageSystemVolumeName)
+	assert.Equal(t, "iscsi", attributes.Protocol)
+	assert.Equal(t, "system-1", settings.Extractors.Lookup("csi-vxflexos-prod.dellemc.com").Extract(volume).StorageSystem)
+
+	// invalid mappings keep the previous ones
+	assert.Nil(t, viper.ReadConfig(bytes.NewBufferString(`
+DRIVER_ATTRIBUTE_MAPPINGS:
+  - driver: block.example.com
+    volumeName:
+      handleSegment: 0
+`)))
+	previous := settings.Extractors
+	updateAttributeMappings(logger, settings)
+	assert.Same(t, previous, settings.Extractors)
+	assert.Equal(t, "iscsi", settings.Extractors.Lookup("block.example.com").Extract(volume).Protocol)
+
+	// new mappings are set on a new registry, leaving the previous one unchanged for the requests using it
+	assert.Nil(t, viper.ReadConfig(bytes.NewBufferString(`
+DRIVER_ATTRIBUTE_MAPPINGS: []
+`)))
+	updateAttributeMappings(logger, settings)
+	assert.NotSame(t, previous, settings.Extractors)
+	assert.Empty(t, settings.Extractors.Lookup("block.example.com").Extract(volume).Protocol)
+	assert.Equal(t, "iscsi", previous.Lookup("block.example.com").Extract(volume).Protocol)
+}
+
+func TestCreateClusterFinder(t *testing.T) {
+	logger := logrus.New()
+	viper.SetConfigType("yaml")
+	defer viper.Reset()
+
+	settings := k8s.DriverSettings{Extractors: k8s.NewExtractorRegistry(), DriverNames: []string{"csi-vxflexos.dellemc.com"}}
+	updateDriverMatchers(logger, &settings)
+	vf := &k8s.VolumeFinder{}
+	vf.SetSettings(settings)
+
+	// no clusters keeps the single volume finder
+	assert.Nil(t, createClusterFinder(logger, vf))
+
+	assert.Nil(t, viper.ReadConfig(bytes.NewBufferString(`
+CLUSTERS:
+  - name: east
+    kubeconfig: /etc/kube/east.yaml
+    context: admin@east
+  - name: west
+    server: https://west.example.com:6443
+    tokenFile: /etc/kube/west-token
+    caFile: /etc/kube/west-ca.crt
+  - name: east
+    context: admin@other
+  - name: north
+    server: https://north.example.com:6443
+  - kubeconfig: /etc/kube/south.yaml
+`)))
+	finder := createClusterFinder(logger, vf)
+	assert.NotNil(t, finder)
+	assert.Len(t, finder.Finders, 2)
+
+	east, west := finder.Finders[0], finder.Finders[1]
+	assert.Equal(t, "east", east.Cluster)
+	assert.Equal(t, &k8s.API{Kubeconfig: "/etc/kube/east.yaml", Context: "admin@east"}, east.API)
+	assert.Equal(t, "west", west.Cluster)
+	api := west.API.(*k8s.API)
+	assert.Equal(t, "https://west.example.com:6443", api.Config.Host)
+	assert.Equal(t, "/etc/kube/west-token", api.Config.BearerTokenFile)
+	assert.Equal(t, "/etc/kube/west-ca.crt", api.Config.CAFile)
+	for _, f := range finder.Finders {
+		assert.Same(t, settings.Extractors, f.Settings().Extractors)
+		assert.Same(t, settings.DriverMatcher, f.Settings().DriverMatcher)
+	}
+
+	// provisioner changes reach every cluster
+	viper.Set("PROVISIONER_NAMES", "csi-powerstore.dellemc.com")
+	config := &ServiceConfig{VolumeFinder: vf, ClusterFinder: finder}
+	handleConfigChange(fsnotify.Event{Name: "config.yaml", Op: fsnotify.Write}, logger, config)
+	for _, f := range finder.Finders {
+		assert.Equal(t, []string{"csi-powerstore.dellemc.com"}, f.Settings().DriverNames)
+		assert.Same(t, vf.Settings().DriverMatcher, f.Settings().DriverMatcher)
+		assert.Same(t, vf.Settings().Extractors, f.Settings().Extractors)
+	}
+
+	service := createService(config, logger)
+	assert.Equal(t, finder, service.VolumeFinder)
+}
+
+func TestCreateClusterFinderInvalid(t *testing.T) {
+	logger := logrus.New()
+	viper.SetConfigType("yaml")
+	defer viper.Reset()
+
+	assert.Nil(t, viper.ReadConfig(bytes.NewBufferString(`
+CLUSTERS:
+  - name: east
+`)))
+	assert.Nil(t, createClusterFinder(logger, &k8s.VolumeFinder{}))
+
+	assert.Nil(t, viper.ReadConfig(bytes.NewBufferString(`
+CLUSTERS: east
+`)))
+	assert.Nil(t, createClusterFinder(logger, &k8s.VolumeFinder{}))
+}
+
+func TestStartInformersClusters(_ *testing.T) {
+	config := &ServiceConfig{
+		VolumeFinder:  &k8s.VolumeFinder{},
+		ClusterFinder: &k8s.ClusterFinder{Finders: []*k8s.VolumeFinder{{Cluster: "east"}}},
+	}
+	startInformers(context.Background(), logrus.New(), config)
+}
+
+func TestParseHistoryRetention(t *testing.T) {
+	tests := []struct {
+		name              string
+		retention         string
+		maxEvents         string
+		expectedRetention time.Duration
+		expectedMaxEvents int
+	}{
+		{"defaults", "", "", 24 * time.Hour, 10000},
+		{"configured", "2h30m", "500", 150 * time.Minute, 500},
+		{"disabled", "0", "0", 0, 0},
+		{"invalid retention", "one day", "500", 24 * time.Hour, 500},
+		{"negative values", "-1h", "-1", 24 * time.Hour, 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer viper.Reset()
+			viper.Set("CHANGE_HISTORY_RETENTION", tt.retention)
+			viper.Set("CHANGE_HISTORY_MAX_EVENTS", tt.maxEvents)
+
+			retention, maxEvents := parseHistoryRetention(logrus.New())
+			assert.Equal(t, tt.expectedRetention, retention)
+			assert.Equal(t, tt.expectedMaxEvents, maxEvents)
+		})
+	}
+}
+
+func TestCreateVolumeHistory(t *testing.T) {
+	logger := logrus.New()
+	defer viper.Reset()
+
+	east := &k8s.VolumeFinder{Cluster: "east", API: &k8s.API{}}
+	west := &k8s.VolumeFinder{Cluster: "west", API: &k8s.API{}}
+	config := &ServiceConfig{
+		VolumeFinder:  &k8s.VolumeFinder{API: &k8s.API{}},
+		ClusterFinder: &k8s.ClusterFinder{Finders: []*k8s.VolumeFinder{east, west}},
+	}
+	history := createVolumeHistory(logger, config)
+	assert.NotNil(t, history)
+	assert.NotNil(t, east.API.(*k8s.API).VolumeEventHandler)
+	assert.NotNil(t, west.API.(*k8s.API).VolumeEventHandler)
+	assert.Nil(t, config.VolumeFinder.API.(*k8s.API).VolumeEventHandler)
+
+	config.VolumeHistory = history
+	service := createService(config, logger)
+	assert.Equal(t, history, service.VolumeHistory)
+
+	// a retention change drops the events it excludes
+	history.Record(k8s.VolumeEvent{Time: time.Now(), PersistentVolume: "pv-1"})
+	viper.Set("CHANGE_HISTORY_RETENTION", "0")
+	handleConfigChange(fsnotify.Event{Name: "config.yaml", Op: fsnotify.Write}, logger, config)
+	assert.Empty(t, history.GetVolumeEvents(time.Time{}))
+
+	// without a history the endpoints are disabled
+	config.VolumeHistory = nil
+	service = createService(config, logger)
+	assert.Nil(t, service.VolumeHistory)
+}
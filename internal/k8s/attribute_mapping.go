@@ -0,0 +1,134 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AttributeSource selects where a storage attribute is read from: a key of the volume attributes, a segment of the
+// volume handle or a constant. The first non-empty of the three is used.
+type AttributeSource struct {
+	// Attribute is the key of the volume attribute holding the value
+	Attribute string `mapstructure:"attribute"`
+	// HandleSegment is the index of the volume handle segment holding the value; negative indexes count from the end
+	HandleSegment *int `mapstructure:"handleSegment"`
+	// Value is a constant value
+	Value string `mapstructure:"value"`
+}
+
+// AttributeMapping declares how the volumes of a driver map to their storage system. Driver is a driver name or a
+// glob pattern such as csi-vxflexos-*.dellemc.com. Attributes without a source are extracted as they would be without
+// the mapping.
+type AttributeMapping struct {
+	Driver string `mapstructure:"driver"`
+	// HandleSeparator splits the volume handle into the segments selected by HandleSegment
+	HandleSeparator string           `mapstructure:"handleSeparator"`
+	StorageSystem   *AttributeSource `mapstructure:"storageSystem"`
+	StoragePool     *AttributeSource `mapstructure:"storagePool"`
+	VolumeName      *AttributeSource `mapstructure:"volumeName"`
+	Protocol        *AttributeSource `mapstructure:"protocol"`
+}
+
+// validate returns an error if the mapping cannot be applied
+func (m AttributeMapping) validate() error {
+	if m.Driver == "" {
+		return errors.New("driver is required")
+	}
+	if _, err := path.Match(m.Driver, ""); err != nil {
+		return fmt.Errorf("invalid driver pattern %q: %v", m.Driver, err)
+	}
+	for name, source := range m.sources() {
+		if source == nil {
+			continue
+		}
+		if source.Attribute == "" && source.HandleSegment == nil && source.Value == "" {
+			return fmt.Errorf("driver %s: %s has no attribute, handleSegment or value", m.Driver, name)
+		}
+		if source.HandleSegment != nil && m.HandleSeparator == "" {
+			return fmt.Errorf("driver %s: %s uses handleSegment without handleSeparator", m.Driver, name)
+		}
+	}
+	return nil
+}
+
+// sources returns the attribute sources of the mapping by configuration key
+func (m AttributeMapping) sources() map[string]*AttributeSource {
+	return map[string]*AttributeSource{
+		"storageSystem": m.StorageSystem,
+		"storagePool":   m.StoragePool,
+		"volumeName":    m.VolumeName,
+		"protocol":      m.Protocol,
+	}
+}
+
+// matches returns true if the mapping applies to the driver
+func (m AttributeMapping) matches(driver string) bool {
+	matched, _ := path.Match(m.Driver, driver)
+	return matched
+}
+
+// mappingExtractor applies an attribute mapping on top of the extractor the driver would use without it
+type mappingExtractor struct {
+	mapping AttributeMapping
+	base    AttributeExtractor
+}
+
+// Extract implements AttributeExtractor
+func (e mappingExtractor) Extract(volume *corev1.PersistentVolume) StorageAttributes {
+	info := e.base.Extract(volume)
+	e.apply(volume.Spec.CSI, e.mapping.StorageSystem, &info.StorageSystem)
+	e.apply(volume.Spec.CSI, e.mapping.StoragePool, &info.StoragePoolName)
+	e.apply(volume.Spec.CSI, e.mapping.VolumeName, &info.StorageSystemVolumeName)
+	e.apply(volume.Spec.CSI, e.mapping.Protocol, &info.Protocol)
+	return info
+}
+
+// apply sets the attribute to the value of its source, if it has one
+func (e mappingExtractor) apply(csi *corev1.CSIPersistentVolumeSource, source *AttributeSource, attribute *string) {
+	if source == nil {
+		return
+	}
+	*attribute = ""
+	if source.Attribute != "" {
+		*attribute = csi.VolumeAttributes[source.Attribute]
+	}
+	if *attribute == "" && source.HandleSegment != nil {
+		*attribute = handleSegment(csi.VolumeHandle, e.mapping.HandleSeparator, *source.HandleSegment)
+	}
+	if *attribute == "" {
+		*attribute = source.Value
+	}
+}
+
+// handleSegment returns a segment of a volume handle; negative indexes count from the end and indexes out of range
+// return an empty string
+func handleSegment(handle, separator string, index int) string {
+	segments := strings.Split(handle, separator)
+	if index < 0 {
+		index += len(segments)
+	}
+	if index < 0 || index >= len(segments) {
+		return ""
+	}
+	return segments[index]
+}
@@ -0,0 +1,146 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func segment(index int) *int {
+	return &index
+}
+
+func Test_AttributeMappings(t *testing.T) {
+	tests := map[string]struct {
+		mappings []k8s.AttributeMapping
+		driver   string
+		handle   string
+		attrs    map[string]string
+		expected k8s.StorageAttributes
+	}{
+		"renamed powerflex driver with its own attribute keys": {
+			mappings: []k8s.AttributeMapping{{
+				Driver:        "csi-vxflexos-prod.dellemc.com",
+				StorageSystem: &k8s.AttributeSource{Attribute: "SystemID"},
+			}},
+			driver: "csi-vxflexos-prod.dellemc.com",
+			attrs:  map[string]string{"SystemID": "4d4a2e5a36080e0f", "Name": "vol-1", "StoragePoolName": "pool-1"},
+			expected: k8s.StorageAttributes{
+				StorageSystem:           "4d4a2e5a36080e0f",
+				StoragePoolName:         "pool-1",
+				StorageSystemVolumeName: "vol-1",
+			},
+		},
+		"third-party driver from handle segments": {
+			mappings: []k8s.AttributeMapping{{
+				Driver:          "*.example.com",
+				HandleSeparator: "/",
+				StorageSystem:   &k8s.AttributeSource{HandleSegment: segment(0)},
+				StoragePool:     &k8s.AttributeSource{HandleSegment: segment(1)},
+				VolumeName:      &k8s.AttributeSource{HandleSegment: segment(-1)},
+				Protocol:        &k8s.AttributeSource{Attribute: "transport", Value: "iscsi"},
+			}},
+			driver: "block.example.com",
+			handle: "array-1/pool-2/vol-3",
+			attrs:  map[string]string{"Name": "ignored"},
+			expected: k8s.StorageAttributes{
+				StorageSystem:           "array-1",
+				StoragePoolName:         "pool-2",
+				StorageSystemVolumeName: "vol-3",
+				Protocol:                "iscsi",
+			},
+		},
+		"handle segment out of range": {
+			mappings: []k8s.AttributeMapping{{
+				Driver:          "block.example.com",
+				HandleSeparator: "/",
+				StoragePool:     &k8s.AttributeSource{HandleSegment: segment(5)},
+			}},
+			driver:   "block.example.com",
+			handle:   "array-1/vol-3",
+			expected: k8s.StorageAttributes{},
+		},
+		"exact name before patterns": {
+			mappings: []k8s.AttributeMapping{
+				{Driver: "*.example.com", Protocol: &k8s.AttributeSource{Value: "pattern"}},
+				{Driver: "block.example.com", Protocol: &k8s.AttributeSource{Value: "exact"}},
+			},
+			driver:   "block.example.com",
+			expected: k8s.StorageAttributes{Protocol: "exact"},
+		},
+		"no matching mapping": {
+			mappings: []k8s.AttributeMapping{
+				{Driver: "*.example.com", Protocol: &k8s.AttributeSource{Value: "pattern"}},
+			},
+			driver:   k8s.CsiDriverPowerMax,
+			handle:   "vol-1",
+			expected: k8s.StorageAttributes{StorageSystemVolumeName: "vol-1", Protocol: "N/A"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := k8s.NewExtractorRegistry()
+			assert.Nil(t, registry.SetMappings(tc.mappings))
+			volume := csiVolume(tc.driver, tc.handle, tc.attrs)
+			assert.Equal(t, tc.expected, registry.Lookup(tc.driver).Extract(volume))
+		})
+	}
+}
+
+func Test_SetMappingsErrors(t *testing.T) {
+	tests := map[string]k8s.AttributeMapping{
+		"no driver":    {Protocol: &k8s.AttributeSource{Value: "nfs"}},
+		"bad pattern":  {Driver: "csi-[.dellemc.com"},
+		"empty source": {Driver: "block.example.com", StorageSystem: &k8s.AttributeSource{}},
+		"no separator": {Driver: "block.example.com", VolumeName: &k8s.AttributeSource{HandleSegment: segment(0)}},
+	}
+	for name, mapping := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := k8s.NewExtractorRegistry()
+			valid := k8s.AttributeMapping{Driver: "block.example.com", Protocol: &k8s.AttributeSource{Value: "nfs"}}
+			assert.Nil(t, registry.SetMappings([]k8s.AttributeMapping{valid}))
+
+			assert.NotNil(t, registry.SetMappings([]k8s.AttributeMapping{mapping}))
+			volume := csiVolume("block.example.com", "", nil)
+			assert.Equal(t, "nfs", registry.Lookup("block.example.com").Extract(volume).Protocol)
+		})
+	}
+}
+
+func Test_WithMappings(t *testing.T) {
+	registry := k8s.NewExtractorRegistry()
+	registry.Register("block.example.com", staticExtractor{StorageSystem: "custom"})
+	nfs := k8s.AttributeMapping{Driver: "block.example.com", Protocol: &k8s.AttributeSource{Value: "nfs"}}
+	assert.Nil(t, registry.SetMappings([]k8s.AttributeMapping{nfs}))
+
+	iscsi := k8s.AttributeMapping{Driver: "block.example.com", Protocol: &k8s.AttributeSource{Value: "iscsi"}}
+	updated, err := registry.WithMappings([]k8s.AttributeMapping{iscsi})
+	assert.Nil(t, err)
+	volume := csiVolume("block.example.com", "vol-1", nil)
+	assert.Equal(t, "iscsi", updated.Lookup("block.example.com").Extract(volume).Protocol)
+	assert.Equal(t, "nfs", registry.Lookup("block.example.com").Extract(volume).Protocol)
+	// registered extractors are kept
+	assert.Equal(t, "custom", updated.Lookup("block.example.com").Extract(volume).StorageSystem)
+
+	_, err = registry.WithMappings([]k8s.AttributeMapping{{Driver: "csi-[.dellemc.com"}})
+	assert.NotNil(t, err)
+	assert.Equal(t, "nfs", registry.Lookup("block.example.com").Extract(volume).Protocol)
+}
@@ -0,0 +1,145 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	tracer "github.com/dell/karavi-topology/internal/tracers"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+)
+
+// ClusterConfig defines a cluster to find volumes in, through a kubeconfig file and context or through the endpoint
+// of its API server and a bearer token
+type ClusterConfig struct {
+	Name                  string `mapstructure:"name"`
+	Kubeconfig            string `mapstructure:"kubeconfig"`
+	Context               string `mapstructure:"context"`
+	Server                string `mapstructure:"server"`
+	Token                 string `mapstructure:"token"`
+	TokenFile             string `mapstructure:"tokenFile"`
+	CAFile                string `mapstructure:"caFile"`
+	InsecureSkipTLSVerify bool   `mapstructure:"insecureSkipTLSVerify"`
+}
+
+// Validate returns an error if the cluster definition is incomplete or ambiguous
+func (c ClusterConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("cluster name is required")
+	}
+	if c.Server == "" && c.Kubeconfig == "" && c.Context == "" {
+		return fmt.Errorf("cluster %s: one of server, kubeconfig or context is required", c.Name)
+	}
+	if c.Server != "" && (c.Kubeconfig != "" || c.Context != "") {
+		return fmt.Errorf("cluster %s: server cannot be combined with kubeconfig or context", c.Name)
+	}
+	if c.Server != "" && c.Token == "" && c.TokenFile == "" {
+		return fmt.Errorf("cluster %s: server requires a token or tokenFile", c.Name)
+	}
+	return nil
+}
+
+// NewAPI returns an API connecting to the cluster
+func (c ClusterConfig) NewAPI() *API {
+	api := &API{Kubeconfig: c.Kubeconfig, Context: c.Context}
+	if c.Server != "" {
+		api.Config = &rest.Config{
+			Host:            c.Server,
+			BearerToken:     c.Token,
+			BearerTokenFile: c.TokenFile,
+			TLSClientConfig: rest.TLSClientConfig{CAFile: c.CAFile, Insecure: c.InsecureSkipTLSVerify},
+		}
+	}
+	return api
+}
+
+// ClusterFinder finds the volumes of several clusters, querying the volume finder of each cluster concurrently.
+// Clusters that fail are logged and left out, so one unreachable cluster does not hide the others; a request only
+// fails when every cluster fails. The finder is not ready while a cluster is still syncing.
+type ClusterFinder struct {
+	Finders []*VolumeFinder
+	Logger  *logrus.Logger
+}
+
+// GetPersistentVolumes will return the persistent volume information of every cluster, in cluster order
+func (c ClusterFinder) GetPersistentVolumes(ctx context.Context) ([]VolumeInfo, error) {
+	ctx, span := tracer.GetTracer(ctx, "ClusterGetPersistentVolumes")
+	defer span.End()
+
+	return queryClusters(c, "persistent volumes", func(f *VolumeFinder) ([]VolumeInfo, error) {
+		return f.GetPersistentVolumes(ctx)
+	})
+}
+
+// GetVolumeSnapshots will return the volume snapshot information of every cluster, in cluster order
+func (c ClusterFinder) GetVolumeSnapshots(ctx context.Context) ([]SnapshotInfo, error) {
+	ctx, span := tracer.GetTracer(ctx, "ClusterGetVolumeSnapshots")
+	defer span.End()
+
+	return queryClusters(c, "volume snapshots", func(f *VolumeFinder) ([]SnapshotInfo, error) {
+		return f.GetVolumeSnapshots(ctx)
+	})
+}
+
+// Ready returns true once the persistent volume cache of every cluster has either completed its initial sync or failed
+// to connect or list, and at least one cluster has synced, so that clusters still syncing are not left out silently
+func (c ClusterFinder) Ready() bool {
+	synced := false
+	for _, finder := range c.Finders {
+		switch {
+		case finder.Ready():
+			synced = true
+		case !finder.SyncFailed():
+			return false
+		}
+	}
+	return synced
+}
+
+// queryClusters runs a query on every cluster concurrently and concatenates the results in cluster order
+func queryClusters[T any](c ClusterFinder, what string, query func(*VolumeFinder) ([]T, error)) ([]T, error) {
+	results := make([][]T, len(c.Finders))
+	errs := make([]error, len(c.Finders))
+	var wg sync.WaitGroup
+	for i, finder := range c.Finders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = query(finder)
+		}()
+	}
+	wg.Wait()
+
+	all := make([]T, 0)
+	failed := make([]error, 0)
+	for i, finder := range c.Finders {
+		if errs[i] != nil {
+			c.Logger.WithError(errs[i]).WithField("cluster", finder.Cluster).Warnf("getting %s; the cluster is left out", what)
+			failed = append(failed, fmt.Errorf("cluster %s: %w", finder.Cluster, errs[i]))
+			continue
+		}
+		all = append(all, results[i]...)
+	}
+	if len(c.Finders) > 0 && len(failed) == len(c.Finders) {
+		return nil, errors.Join(failed...)
+	}
+	return all, nil
+}
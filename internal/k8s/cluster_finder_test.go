@@ -0,0 +1,187 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/k8s/mocks"
+	"github.com/sirupsen/logrus"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// clusterFinder returns the volume finder of a cluster holding one PowerMax volume, or failing to list volumes
+func clusterFinder(ctrl *gomock.Controller, cluster string, err error) *k8s.VolumeFinder {
+	api := mocks.NewMockVolumeGetter(ctrl)
+	if err != nil {
+		api.EXPECT().GetPersistentVolumes().AnyTimes().Return(nil, err)
+		api.EXPECT().GetVolumeSnapshotContents().AnyTimes().Return(nil, err)
+		api.EXPECT().HasSynced().AnyTimes().Return(false)
+		api.EXPECT().SyncFailed().AnyTimes().Return(true)
+	} else {
+		volumes := &corev1.PersistentVolumeList{Items: []corev1.PersistentVolume{{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-" + cluster},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: k8s.CsiDriverPowerMax},
+				},
+				ClaimRef: &corev1.ObjectReference{Name: "pvc-1", Namespace: "namespace-1"},
+			},
+		}}}
+		api.EXPECT().GetPersistentVolumes().AnyTimes().Return(volumes, nil)
+		api.EXPECT().GetPods().AnyTimes().Return(&corev1.PodList{}, nil)
+		api.EXPECT().GetReplicaSets().AnyTimes().Return(&appsv1.ReplicaSetList{}, nil)
+		api.EXPECT().GetVolumeAttachments().AnyTimes().Return(&storagev1.VolumeAttachmentList{}, nil)
+		api.EXPECT().GetPersistentVolumeClaims().AnyTimes().Return(&corev1.PersistentVolumeClaimList{}, nil)
+		api.EXPECT().GetStorageClasses().AnyTimes().Return(&storagev1.StorageClassList{}, nil)
+		api.EXPECT().GetVolumeSnapshots().AnyTimes().Return(nil, k8s.ErrResourceNotInstalled)
+		api.EXPECT().GetVolumeSnapshotContents().AnyTimes().Return(nil, k8s.ErrResourceNotInstalled)
+		api.EXPECT().HasSynced().AnyTimes().Return(true)
+	}
+	return &k8s.VolumeFinder{
+		API:         api,
+		DriverNames: []string{k8s.CsiDriverPowerMax},
+		Logger:      logrus.New(),
+		Cluster:     cluster,
+	}
+}
+
+func Test_ClusterFinderGetPersistentVolumes(t *testing.T) {
+	tests := map[string]struct {
+		failing  []string
+		expected map[string]string
+		hasError bool
+		ready    bool
+	}{
+		"every cluster": {
+			expected: map[string]string{"pv-east": "east", "pv-west": "west", "pv-north": "north"},
+			ready:    true,
+		},
+		"failing cluster is left out": {
+			failing:  []string{"west"},
+			expected: map[string]string{"pv-east": "east", "pv-north": "north"},
+			ready:    true,
+		},
+		"every cluster failing": {
+			failing:  []string{"east", "west", "north"},
+			hasError: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			finder := k8s.ClusterFinder{Logger: logrus.New()}
+			for _, cluster := range []string{"east", "west", "north"} {
+				var err error
+				if k8s.Contains(tc.failing, cluster) {
+					err = errors.New("connection refused")
+				}
+				finder.Finders = append(finder.Finders, clusterFinder(ctrl, cluster, err))
+			}
+
+			volumes, err := finder.GetPersistentVolumes(context.Background())
+			assert.Equal(t, tc.ready, finder.Ready())
+			if tc.hasError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "cluster west: connection refused")
+				return
+			}
+			assert.NoError(t, err)
+			result := make(map[string]string)
+			for _, volume := range volumes {
+				result[volume.PersistentVolume] = volume.Cluster
+			}
+			assert.Equal(t, tc.expected, result)
+			assert.Equal(t, "pv-east", volumes[0].PersistentVolume)
+
+			snapshots, err := finder.GetVolumeSnapshots(context.Background())
+			assert.NoError(t, err)
+			assert.Empty(t, snapshots)
+		})
+	}
+}
+
+func Test_ClusterFinderReady(t *testing.T) {
+	const (
+		synced  = "synced"
+		syncing = "syncing"
+		failed  = "failed"
+	)
+	tests := map[string]struct {
+		clusters []string
+		ready    bool
+	}{
+		"every cluster synced":  {clusters: []string{synced, synced}, ready: true},
+		"failed cluster":        {clusters: []string{synced, failed}, ready: true},
+		"cluster still syncing": {clusters: []string{synced, syncing}},
+		"every cluster failed":  {clusters: []string{failed, failed}},
+		"no cluster synced yet": {clusters: []string{syncing, failed}},
+		"no cluster":            {},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			finder := k8s.ClusterFinder{Logger: logrus.New()}
+			for _, state := range tc.clusters {
+				api := mocks.NewMockVolumeGetter(ctrl)
+				api.EXPECT().HasSynced().AnyTimes().Return(state == synced)
+				api.EXPECT().SyncFailed().AnyTimes().Return(state == failed)
+				finder.Finders = append(finder.Finders, &k8s.VolumeFinder{API: api, Cluster: state})
+			}
+			assert.Equal(t, tc.ready, finder.Ready())
+		})
+	}
+}
+
+func Test_ClusterConfig(t *testing.T) {
+	tests := map[string]struct {
+		config   k8s.ClusterConfig
+		hasError bool
+	}{
+		"kubeconfig":           {config: k8s.ClusterConfig{Name: "east", Kubeconfig: "/clusters/east.yaml", Context: "east"}},
+		"endpoint and token":   {config: k8s.ClusterConfig{Name: "west", Server: "https://10.0.0.1:6443", TokenFile: "/clusters/west/token"}},
+		"no name":              {config: k8s.ClusterConfig{Kubeconfig: "/clusters/east.yaml"}, hasError: true},
+		"no connection":        {config: k8s.ClusterConfig{Name: "east"}, hasError: true},
+		"server and context":   {config: k8s.ClusterConfig{Name: "east", Server: "https://10.0.0.1:6443", Token: "token", Context: "east"}, hasError: true},
+		"server without token": {config: k8s.ClusterConfig{Name: "west", Server: "https://10.0.0.1:6443"}, hasError: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.hasError, tc.config.Validate() != nil)
+		})
+	}
+
+	api := k8s.ClusterConfig{Name: "west", Server: "https://10.0.0.1:6443", Token: "token", CAFile: "/clusters/west/ca.crt"}.NewAPI()
+	assert.Equal(t, "https://10.0.0.1:6443", api.Config.Host)
+	assert.Equal(t, "token", api.Config.BearerToken)
+	assert.Equal(t, "/clusters/west/ca.crt", api.Config.TLSClientConfig.CAFile)
+
+	api = k8s.ClusterConfig{Name: "east", Kubeconfig: "/clusters/east.yaml", Context: "east"}.NewAPI()
+	assert.Nil(t, api.Config)
+	assert.Equal(t, "/clusters/east.yaml", api.Kubeconfig)
+	assert.Equal(t, "east", api.Context)
+}
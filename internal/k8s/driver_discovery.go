@@ -0,0 +1,97 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s
+
+// DellDriverPatterns match the names the Dell CSI drivers are installed under, including renamed instances such as
+// csi-vxflexos-prod.dellemc.com
+var DellDriverPatterns = []string{
+	"csi-vxflexos*.dellemc.com",
+	"csi-powerstore*.dellemc.com",
+	"csi-isilon*.dellemc.com",
+	"csi-powermax*.dellemc.com",
+	"csi-unity*.dellemc.com",
+}
+
+// drivers returns the function selecting the drivers whose volumes are returned with the settings: the patterns of
+// DriverMatcher, or DriverNames when there is no matcher, or else the installed CSI drivers matched by Discovery.
+// Drivers are discovered from the informer cache on every call, so drivers installed or removed are picked up by the
+// next query.
+func (f VolumeFinder) drivers(settings DriverSettings) (func(string) bool, error) {
+	switch {
+	case settings.discovers():
+		names, err := f.discoverDrivers(settings.Discovery)
+		if err != nil {
+			return nil, err
+		}
+		return func(driver string) bool { return Contains(names, driver) }, nil
+	case settings.DriverMatcher != nil && !settings.DriverMatcher.Empty():
+		return settings.DriverMatcher.Match, nil
+	case settings.DriverMatcher == nil:
+		return func(driver string) bool { return Contains(settings.DriverNames, driver) }, nil
+	}
+	return func(string) bool { return false }, nil
+}
+
+// DiscoversDrivers returns true if the drivers are discovered from the installed CSI drivers rather than configured
+func (f VolumeFinder) DiscoversDrivers() bool {
+	return f.Settings().discovers()
+}
+
+// discovers returns true if there are no driver names or patterns, only exclusions, and Discovery is set
+func (s DriverSettings) discovers() bool {
+	switch {
+	case s.Discovery == nil:
+		return false
+	case s.DriverMatcher != nil:
+		return s.DriverMatcher.Empty()
+	}
+	return len(s.DriverNames) == 0
+}
+
+// configuredDrivers returns the function selecting drivers from the current settings only: like drivers, but matching
+// the Discovery patterns rather than the installed CSI drivers, so that the selection does not depend on the cluster
+func (f VolumeFinder) configuredDrivers() func(string) bool {
+	settings := f.Settings()
+	switch {
+	case settings.DriverMatcher != nil && !settings.DriverMatcher.Empty():
+		return settings.DriverMatcher.Match
+	case settings.DriverMatcher == nil && len(settings.DriverNames) > 0:
+		return func(driver string) bool { return Contains(settings.DriverNames, driver) }
+	case settings.Discovery != nil:
+		return settings.Discovery.Match
+	}
+	return func(string) bool { return false }
+}
+
+// discoverDrivers returns the names of the installed CSI drivers matched by discovery. No drivers are discovered when
+// the CSI drivers cannot be listed; other errors are returned, so that callers do not mistake a cache still loading for
+// a cluster without drivers.
+func (f VolumeFinder) discoverDrivers(discovery *DriverMatcher) ([]string, error) {
+	drivers, err := f.API.GetCSIDrivers()
+	if err != nil {
+		return nil, f.optional(err, "getting CSI drivers; no drivers are discovered")
+	}
+
+	names := make([]string, 0, len(drivers.Items))
+	for _, driver := range drivers.Items {
+		if discovery.Match(driver.Name) {
+			names = append(names, driver.Name)
+		}
+	}
+	f.Logger.WithField("drivers", names).Debug("volumefinder discovered drivers")
+	return names, nil
+}
@@ -0,0 +1,167 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/k8s/mocks"
+	"github.com/sirupsen/logrus"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_VolumeFinderDiscoversDrivers(t *testing.T) {
+	volume := func(name, driver string) corev1.PersistentVolume {
+		return corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: driver},
+				},
+				ClaimRef: &corev1.ObjectReference{Name: "pvc-" + name, Namespace: "namespace-1"},
+			},
+		}
+	}
+	volumes := &corev1.PersistentVolumeList{Items: []corev1.PersistentVolume{
+		volume("pv-1", "csi-vxflexos-prod.dellemc.com"),
+		volume("pv-2", "csi-unity.dellemc.com"),
+		volume("pv-3", "ebs.csi.aws.com"),
+	}}
+	drivers := &storagev1.CSIDriverList{Items: []storagev1.CSIDriver{
+		{ObjectMeta: metav1.ObjectMeta{Name: "csi-unity.dellemc.com"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "csi-vxflexos-prod.dellemc.com"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ebs.csi.aws.com"}},
+	}}
+	dell, _ := k8s.NewDriverMatcher(k8s.DellDriverPatterns)
+	unity, _ := k8s.NewDriverMatcher([]string{"/csi-unity\\..*/"})
+	dellExceptUnity, _ := k8s.NewDriverMatcher(append([]string{"!csi-unity*"}, k8s.DellDriverPatterns...))
+	exclusions, _ := k8s.NewDriverMatcher([]string{"!csi-unity*"})
+	provisioners, _ := k8s.NewDriverMatcher([]string{"*.aws.com"})
+
+	tests := map[string]struct {
+		driverNames []string
+		matcher     *k8s.DriverMatcher
+		discovery   *k8s.DriverMatcher
+		csiDrivers  *storagev1.CSIDriverList
+		csiErr      error
+		expected    []string
+	}{
+		"dell drivers": {
+			discovery:  dell,
+			csiDrivers: drivers,
+			expected:   []string{"pv-1", "pv-2"},
+		},
+		"configured patterns": {
+			discovery:  unity,
+			csiDrivers: drivers,
+			expected:   []string{"pv-2"},
+		},
+		"excluded drivers": {
+			matcher:    exclusions,
+			discovery:  dellExceptUnity,
+			csiDrivers: drivers,
+			expected:   []string{"pv-1"},
+		},
+		"provisioner names take precedence": {
+			driverNames: []string{"ebs.csi.aws.com"},
+			discovery:   dell,
+			expected:    []string{"pv-3"},
+		},
+		"provisioner patterns take precedence": {
+			driverNames: []string{"ebs.csi.aws.com"},
+			matcher:     provisioners,
+			discovery:   dell,
+			expected:    []string{"pv-3"},
+		},
+		"listing drivers is denied": {
+			discovery: dell,
+			csiErr:    fmt.Errorf("CSI drivers %w: forbidden", k8s.ErrListDenied),
+			expected:  []string{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			api := mocks.NewMockVolumeGetter(ctrl)
+			api.EXPECT().GetPersistentVolumes().Times(1).Return(volumes, nil)
+			api.EXPECT().GetPods().Times(1).Return(&corev1.PodList{}, nil)
+			api.EXPECT().GetReplicaSets().Times(1).Return(&appsv1.ReplicaSetList{}, nil)
+			api.EXPECT().GetVolumeAttachments().Times(1).Return(&storagev1.VolumeAttachmentList{}, nil)
+			api.EXPECT().GetPersistentVolumeClaims().Times(1).Return(&corev1.PersistentVolumeClaimList{}, nil)
+			api.EXPECT().GetStorageClasses().Times(1).Return(&storagev1.StorageClassList{}, nil)
+			discovers := tc.driverNames == nil || (tc.matcher != nil && tc.matcher.Empty())
+			if discovers {
+				api.EXPECT().GetCSIDrivers().Times(1).Return(tc.csiDrivers, tc.csiErr)
+			}
+
+			finder := k8s.VolumeFinder{
+				API:           api,
+				DriverNames:   tc.driverNames,
+				DriverMatcher: tc.matcher,
+				Discovery:     tc.discovery,
+				Logger:        logrus.New(),
+			}
+			assert.Equal(t, discovers, finder.DiscoversDrivers())
+			result, err := finder.GetPersistentVolumes(context.Background())
+			assert.NoError(t, err)
+			names := make([]string, 0)
+			for _, info := range result {
+				names = append(names, info.PersistentVolume)
+			}
+			assert.Equal(t, tc.expected, names)
+			ctrl.Finish()
+		})
+	}
+}
+
+func Test_DiscoverDriversNotSynced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	api := mocks.NewMockVolumeGetter(ctrl)
+	api.EXPECT().GetPersistentVolumes().Times(1).Return(&corev1.PersistentVolumeList{}, nil)
+	api.EXPECT().GetCSIDrivers().Times(1).Return(nil, k8s.ErrCacheNotSynced)
+
+	dell, _ := k8s.NewDriverMatcher(k8s.DellDriverPatterns)
+	finder := k8s.VolumeFinder{API: api, Discovery: dell, Logger: logrus.New()}
+	_, err := finder.GetPersistentVolumes(context.Background())
+	assert.ErrorIs(t, err, k8s.ErrCacheNotSynced)
+}
+
+func Test_VolumeFinderSettings(t *testing.T) {
+	matcher, err := k8s.NewDriverMatcher([]string{"csi-vxflexos.dellemc.com"})
+	assert.NoError(t, err)
+
+	finder := k8s.VolumeFinder{DriverNames: []string{"csi-vxflexos.dellemc.com"}, DriverMatcher: matcher}
+	assert.Equal(t, []string{"csi-vxflexos.dellemc.com"}, finder.Settings().DriverNames)
+	assert.Same(t, matcher, finder.Settings().DriverMatcher)
+
+	extractors := k8s.NewExtractorRegistry()
+	finder.SetSettings(k8s.DriverSettings{DriverNames: []string{"csi-powerstore.dellemc.com"}, Extractors: extractors})
+	settings := finder.Settings()
+	assert.Equal(t, []string{"csi-powerstore.dellemc.com"}, settings.DriverNames)
+	assert.Nil(t, settings.DriverMatcher)
+	assert.Same(t, extractors, settings.Extractors)
+}
@@ -0,0 +1,269 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Names of the built-in Dell CSI drivers
+const (
+	CsiDriverPowerFlex  = "csi-vxflexos.dellemc.com"
+	CsiDriverPowerStore = "csi-powerstore.dellemc.com"
+	CsiDriverPowerScale = "csi-isilon.dellemc.com"
+	CsiDriverPowerMax   = "csi-powermax.dellemc.com"
+	CsiDriverUnity      = "csi-unity.dellemc.com"
+)
+
+// StorageAttributes identifies the volume backing a persistent volume on its storage system
+type StorageAttributes struct {
+	StorageSystem           string
+	StoragePoolName         string
+	StorageSystemVolumeName string
+	Protocol                string
+}
+
+// AttributeExtractor maps the CSI source of a persistent volume to the volume on the storage system of its driver.
+// Attributes the driver does not report are left empty.
+type AttributeExtractor interface {
+	Extract(volume *corev1.PersistentVolume) StorageAttributes
+}
+
+// GenericExtractor reads the volume attributes set by most Dell drivers: Name, StoragePoolName, Protocol and
+// StorageSystem, falling back to arrayID for the storage system
+type GenericExtractor struct{}
+
+// Extract implements AttributeExtractor
+func (GenericExtractor) Extract(volume *corev1.PersistentVolume) StorageAttributes {
+	attributes := volume.Spec.CSI.VolumeAttributes
+	info := StorageAttributes{
+		StorageSystem:           attributes["StorageSystem"],
+		StoragePoolName:         attributes["StoragePoolName"],
+		StorageSystemVolumeName: attributes["Name"],
+		Protocol:                attributes["Protocol"],
+	}
+	if info.StorageSystem == "" {
+		info.StorageSystem = attributes["arrayID"]
+	}
+	return info
+}
+
+// PowerFlexExtractor extracts the attributes of PowerFlex volumes, which report the storage system ID and pool
+type PowerFlexExtractor struct {
+	GenericExtractor
+}
+
+// PowerStoreExtractor extracts the attributes of PowerStore volumes, which report the array ID and protocol but no
+// storage pool
+type PowerStoreExtractor struct {
+	GenericExtractor
+}
+
+// PowerScaleExtractor extracts the attributes of PowerScale volumes: the storage system is the cluster name and
+// access zone, and the protocol is always NFS
+type PowerScaleExtractor struct{}
+
+// Extract implements AttributeExtractor
+func (PowerScaleExtractor) Extract(volume *corev1.PersistentVolume) StorageAttributes {
+	attributes := volume.Spec.CSI.VolumeAttributes
+	return StorageAttributes{
+		StorageSystem:           attributes["ClusterName"] + ":" + attributes["AccessZone"],
+		StoragePoolName:         attributes["StoragePoolName"],
+		StorageSystemVolumeName: attributes["Name"],
+		Protocol:                ProtocolNfs,
+	}
+}
+
+// PowerMaxExtractor extracts the attributes of PowerMax volumes: the storage system is the Symmetrix ID, the pool is
+// the storage resource pool and the volume name is parsed from the volume handle
+type PowerMaxExtractor struct{}
+
+// Extract implements AttributeExtractor
+func (PowerMaxExtractor) Extract(volume *corev1.PersistentVolume) StorageAttributes {
+	attributes := volume.Spec.CSI.VolumeAttributes
+	info := StorageAttributes{
+		StorageSystem:           attributes["powermax/SYMID"],
+		StoragePoolName:         attributes["SRP"],
+		StorageSystemVolumeName: parsePowerMaxVolumeName(volume.Spec.CSI.VolumeHandle),
+		Protocol:                attributes["Protocol"],
+	}
+	if info.Protocol == "" {
+		info.Protocol = "N/A"
+	}
+	return info
+}
+
+// UnityExtractor extracts the attributes of Unity XT volumes from their arrayId, protocol and storagePool volume
+// attributes, falling back to the volume handle for the array ID and protocol
+type UnityExtractor struct{}
+
+// Extract implements AttributeExtractor
+func (UnityExtractor) Extract(volume *corev1.PersistentVolume) StorageAttributes {
+	attributes := volume.Spec.CSI.VolumeAttributes
+	name, protocol, arrayID, _ := parseUnityVolumeHandle(volume.Spec.CSI.VolumeHandle)
+	info := StorageAttributes{
+		StorageSystem:           attributes["arrayId"],
+		StoragePoolName:         attributes["storagePool"],
+		StorageSystemVolumeName: name,
+		Protocol:                attributes["protocol"],
+	}
+	if info.StorageSystem == "" {
+		info.StorageSystem = arrayID
+	}
+	if info.Protocol == "" {
+		info.Protocol = protocol
+	}
+	return info
+}
+
+// parseUnityVolumeHandle splits a Unity XT volume handle, name-protocol-arrayID-id, into its parts. The name may
+// contain dashes, so the handle is parsed from the end; handles of another form return the handle as the name.
+func parseUnityVolumeHandle(volumeHandle string) (name, protocol, arrayID, id string) {
+	ele := strings.Split(volumeHandle, "-")
+	if len(ele) < 4 {
+		return volumeHandle, "", "", ""
+	}
+	n := len(ele)
+	return strings.Join(ele[:n-3], "-"), ele[n-3], ele[n-2], ele[n-1]
+}
+
+// ExtractorRegistry holds the attribute extractor of each CSI driver. Drivers without an extractor of their own use
+// the extractor registered for the built-in driver whose short name they contain, so a PowerScale driver installed
+// as csi-isilon-prod.dellemc.com is still handled, and GenericExtractor otherwise. Configured attribute mappings are
+// applied on top of the extractor of the drivers they match.
+type ExtractorRegistry struct {
+	lock       sync.RWMutex
+	extractors map[string]AttributeExtractor
+	mappings   []AttributeMapping
+}
+
+// NewExtractorRegistry returns a registry with the extractors of the built-in Dell drivers
+func NewExtractorRegistry() *ExtractorRegistry {
+	r := &ExtractorRegistry{extractors: make(map[string]AttributeExtractor)}
+	r.Register(CsiDriverPowerFlex, PowerFlexExtractor{})
+	r.Register(CsiDriverPowerStore, PowerStoreExtractor{})
+	r.Register(CsiDriverPowerScale, PowerScaleExtractor{})
+	r.Register(CsiDriverPowerMax, PowerMaxExtractor{})
+	r.Register(CsiDriverUnity, UnityExtractor{})
+	return r
+}
+
+// Register sets the extractor of a driver, replacing any previous one
+func (r *ExtractorRegistry) Register(driver string, extractor AttributeExtractor) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.extractors[driver] = extractor
+}
+
+// SetMappings validates the attribute mappings and replaces the configured ones. A mapping naming the driver exactly
+// takes precedence over patterns, which are tried in order. On error the configured mappings are left unchanged.
+func (r *ExtractorRegistry) SetMappings(mappings []AttributeMapping) error {
+	for _, mapping := range mappings {
+		if err := mapping.validate(); err != nil {
+			return err
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.mappings = append([]AttributeMapping(nil), mappings...)
+	return nil
+}
+
+// WithMappings validates the attribute mappings and returns a copy of the registry with the configured mappings
+// replaced by them, leaving the registry unchanged so that requests using it are not affected
+func (r *ExtractorRegistry) WithMappings(mappings []AttributeMapping) (*ExtractorRegistry, error) {
+	registry := &ExtractorRegistry{}
+	r.lock.RLock()
+	registry.extractors = make(map[string]AttributeExtractor, len(r.extractors))
+	for driver, extractor := range r.extractors {
+		registry.extractors[driver] = extractor
+	}
+	registry.mappings = r.mappings
+	r.lock.RUnlock()
+
+	if err := registry.SetMappings(mappings); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Lookup returns the extractor of a driver
+func (r *ExtractorRegistry) Lookup(driver string) AttributeExtractor {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	extractor := r.lookup(driver)
+	for _, mapping := range r.mappings {
+		if mapping.Driver == driver {
+			return mappingExtractor{mapping: mapping, base: extractor}
+		}
+	}
+	for _, mapping := range r.mappings {
+		if mapping.matches(driver) {
+			return mappingExtractor{mapping: mapping, base: extractor}
+		}
+	}
+	return extractor
+}
+
+// lookup returns the registered extractor of a driver, ignoring attribute mappings
+func (r *ExtractorRegistry) lookup(driver string) AttributeExtractor {
+	if extractor, ok := r.extractors[driver]; ok {
+		return extractor
+	}
+
+	names := make([]string, 0, len(r.extractors))
+	for name := range r.extractors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if short := shortDriverName(name); short != "" && strings.Contains(driver, short) {
+			return r.extractors[name]
+		}
+	}
+	return GenericExtractor{}
+}
+
+// shortDriverName returns the name of a driver without its csi- prefix and domain, such as isilon for
+// csi-isilon.dellemc.com; it is empty for drivers not named that way
+func shortDriverName(driver string) string {
+	if !strings.HasPrefix(driver, "csi-") {
+		return ""
+	}
+	short, _, _ := strings.Cut(strings.TrimPrefix(driver, "csi-"), ".")
+	return short
+}
+
+// parsePowerMaxVolumeName parse PowerMax PV volumeHandle and return storage volume name
+func parsePowerMaxVolumeName(volumeHandle string) string {
+	ele := strings.Split(volumeHandle, "-")
+	if len(ele) == 7 {
+		return fmt.Sprintf("%s:%s", ele[6], strings.Join(ele[0:5], "-"))
+	}
+
+	return volumeHandle
+}
+
+// defaultExtractors is used by volume finders without an extractor registry
+var defaultExtractors = NewExtractorRegistry()
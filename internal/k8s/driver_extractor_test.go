@@ -0,0 +1,179 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func csiVolume(driver, handle string, attributes map[string]string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:           driver,
+					VolumeHandle:     handle,
+					VolumeAttributes: attributes,
+				},
+			},
+		},
+	}
+}
+
+func Test_AttributeExtractors(t *testing.T) {
+	tests := map[string]struct {
+		volume   *corev1.PersistentVolume
+		expected k8s.StorageAttributes
+	}{
+		"powerflex": {
+			volume: csiVolume(k8s.CsiDriverPowerFlex, "7b5f0b6e00000001", map[string]string{
+				"Name":            "k8s-2fb5ee3a6c",
+				"StoragePoolName": "pool-1",
+				"StorageSystem":   "4d4a2e5a36080e0f",
+			}),
+			expected: k8s.StorageAttributes{
+				StorageSystem:           "4d4a2e5a36080e0f",
+				StoragePoolName:         "pool-1",
+				StorageSystemVolumeName: "k8s-2fb5ee3a6c",
+			},
+		},
+		"powerstore": {
+			volume: csiVolume(k8s.CsiDriverPowerStore, "d3b1b2c5/PS000000000001/scsi", map[string]string{
+				"Protocol": "scsi",
+				"arrayID":  "PS000000000001",
+			}),
+			expected: k8s.StorageAttributes{
+				StorageSystem: "PS000000000001",
+				Protocol:      "scsi",
+			},
+		},
+		"powerscale": {
+			volume: csiVolume(k8s.CsiDriverPowerScale, "k8s-0b6a6c=_=_=15=_=_=System", map[string]string{
+				"Name":        "k8s-0b6a6c",
+				"AccessZone":  "System",
+				"ClusterName": "cluster-1",
+			}),
+			expected: k8s.StorageAttributes{
+				StorageSystem:           "cluster-1:System",
+				StorageSystemVolumeName: "k8s-0b6a6c",
+				Protocol:                k8s.ProtocolNfs,
+			},
+		},
+		"powermax": {
+			volume: csiVolume(k8s.CsiDriverPowerMax, "csi-ZYA-pmax-4723028a00-powermax-000120000606-0012D", map[string]string{
+				"SRP":            "SRP_1",
+				"powermax/SYMID": "000120000606",
+			}),
+			expected: k8s.StorageAttributes{
+				StorageSystem:           "000120000606",
+				StoragePoolName:         "SRP_1",
+				StorageSystemVolumeName: "0012D:csi-ZYA-pmax-4723028a00-powermax",
+				Protocol:                "N/A",
+			},
+		},
+		"powermax unparsable handle": {
+			volume: csiVolume(k8s.CsiDriverPowerMax, "vol-1", map[string]string{"Protocol": "FC"}),
+			expected: k8s.StorageAttributes{
+				StorageSystemVolumeName: "vol-1",
+				Protocol:                "FC",
+			},
+		},
+		"unity": {
+			volume: csiVolume(k8s.CsiDriverUnity, "csivol-2fb5ee3a6c-iSCSI-apm00213404195-sv_1234", map[string]string{
+				"arrayId":     "apm00213404195",
+				"protocol":    "iSCSI",
+				"storagePool": "pool_1",
+			}),
+			expected: k8s.StorageAttributes{
+				StorageSystem:           "apm00213404195",
+				StoragePoolName:         "pool_1",
+				StorageSystemVolumeName: "csivol-2fb5ee3a6c",
+				Protocol:                "iSCSI",
+			},
+		},
+		"unity without attributes": {
+			volume: csiVolume(k8s.CsiDriverUnity, "csivol-nfs-share-NFS-apm00213404195-fs_12", nil),
+			expected: k8s.StorageAttributes{
+				StorageSystem:           "apm00213404195",
+				StorageSystemVolumeName: "csivol-nfs-share",
+				Protocol:                "NFS",
+			},
+		},
+		"unity unparsable handle": {
+			volume: csiVolume(k8s.CsiDriverUnity, "sv_1234", map[string]string{"protocol": "FC"}),
+			expected: k8s.StorageAttributes{
+				StorageSystemVolumeName: "sv_1234",
+				Protocol:                "FC",
+			},
+		},
+		"renamed powerscale driver": {
+			volume: csiVolume("csi-isilon-prod.dellemc.com", "", map[string]string{
+				"AccessZone":  "zone-1",
+				"ClusterName": "cluster-2",
+			}),
+			expected: k8s.StorageAttributes{
+				StorageSystem: "cluster-2:zone-1",
+				Protocol:      k8s.ProtocolNfs,
+			},
+		},
+		"unknown driver": {
+			volume: csiVolume("another-csi-driver.example.com", "", map[string]string{
+				"Name":          "vol-1",
+				"StorageSystem": "system-1",
+				"Protocol":      "iscsi",
+			}),
+			expected: k8s.StorageAttributes{
+				StorageSystem:           "system-1",
+				StorageSystemVolumeName: "vol-1",
+				Protocol:                "iscsi",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := k8s.NewExtractorRegistry()
+			extractor := registry.Lookup(tc.volume.Spec.CSI.Driver)
+			assert.Equal(t, tc.expected, extractor.Extract(tc.volume))
+		})
+	}
+}
+
+type staticExtractor k8s.StorageAttributes
+
+func (e staticExtractor) Extract(_ *corev1.PersistentVolume) k8s.StorageAttributes {
+	return k8s.StorageAttributes(e)
+}
+
+func Test_ExtractorRegistryRegister(t *testing.T) {
+	registry := k8s.NewExtractorRegistry()
+	custom := staticExtractor{StorageSystem: "custom"}
+	registry.Register("csi.example.com", custom)
+	registry.Register(k8s.CsiDriverPowerStore, custom)
+
+	assert.Equal(t, custom, registry.Lookup("csi.example.com"))
+	assert.Equal(t, custom, registry.Lookup(k8s.CsiDriverPowerStore))
+	assert.Equal(t, k8s.PowerMaxExtractor{}, registry.Lookup(k8s.CsiDriverPowerMax))
+	assert.Equal(t, k8s.GenericExtractor{}, registry.Lookup("csi.other.com"))
+}
@@ -0,0 +1,164 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DriverPattern matches driver names with a glob pattern such as csi-*.dellemc.com or, when enclosed in slashes, a
+// regular expression such as /csi-(unity|powermax).*/ that must match the whole name
+type DriverPattern struct {
+	pattern string
+	regexp  *regexp.Regexp
+}
+
+// ParseDriverPattern compiles a driver pattern
+func ParseDriverPattern(pattern string) (DriverPattern, error) {
+	pattern = strings.TrimSpace(pattern)
+	if strings.HasPrefix(pattern, "/") {
+		if len(pattern) == 1 || !strings.HasSuffix(pattern, "/") {
+			return DriverPattern{}, fmt.Errorf("invalid driver pattern %q: regular expression is missing its closing slash", pattern)
+		}
+		re, err := regexp.Compile("^(?:" + pattern[1:len(pattern)-1] + ")$")
+		if err != nil {
+			return DriverPattern{}, fmt.Errorf("invalid driver pattern %q: %v", pattern, err)
+		}
+		return DriverPattern{pattern: pattern, regexp: re}, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return DriverPattern{}, fmt.Errorf("invalid driver pattern %q: %v", pattern, err)
+	}
+	return DriverPattern{pattern: pattern}, nil
+}
+
+// Match returns true if the driver name matches the pattern
+func (p DriverPattern) Match(driver string) bool {
+	if p.regexp != nil {
+		return p.regexp.MatchString(driver)
+	}
+	matched, _ := path.Match(p.pattern, driver)
+	return matched
+}
+
+// String returns the pattern as written
+func (p DriverPattern) String() string {
+	return p.pattern
+}
+
+// SplitDriverPatterns splits a comma separated list of driver patterns. Commas inside a regular expression enclosed in
+// slashes, such as /csi-[a-z]{1,2}\.dellemc\.com/, do not split it: the expression runs to the first slash followed by a
+// comma or the end of the list.
+func SplitDriverPatterns(list string) []string {
+	var entries []string
+	for list != "" {
+		entry := strings.TrimLeft(list, " \t")
+		end := strings.Index(entry, ",")
+		if expression := strings.TrimPrefix(entry, "!"); strings.HasPrefix(expression, "/") {
+			offset := len(entry) - len(expression) + 1
+			for i := offset; i < len(entry); i++ {
+				if entry[i] != '/' {
+					continue
+				}
+				if rest := strings.TrimLeft(entry[i+1:], " \t"); rest == "" || rest[0] == ',' {
+					end = len(entry) - len(rest)
+					if rest == "" {
+						end = -1
+					}
+					break
+				}
+			}
+		}
+		if end < 0 {
+			entries = append(entries, entry)
+			break
+		}
+		entries = append(entries, entry[:end])
+		list = entry[end+1:]
+	}
+	return entries
+}
+
+// DriverMatcher selects driver names with a list of driver patterns. Entries prefixed with ! are exclusions: a driver
+// matches when it matches at least one pattern and no exclusion.
+type DriverMatcher struct {
+	entries []string
+	include []DriverPattern
+	exclude []DriverPattern
+}
+
+// NewDriverMatcher compiles a list of driver patterns and exclusions, ignoring empty entries
+func NewDriverMatcher(entries []string) (*DriverMatcher, error) {
+	m := &DriverMatcher{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		exclusion := strings.HasPrefix(entry, "!")
+		pattern, err := ParseDriverPattern(strings.TrimPrefix(entry, "!"))
+		if err != nil {
+			return nil, err
+		}
+		if exclusion {
+			m.exclude = append(m.exclude, pattern)
+		} else {
+			m.include = append(m.include, pattern)
+		}
+		m.entries = append(m.entries, entry)
+	}
+	return m, nil
+}
+
+// Match returns true if the driver matches a pattern and no exclusion
+func (m *DriverMatcher) Match(driver string) bool {
+	for _, pattern := range m.exclude {
+		if pattern.Match(driver) {
+			return false
+		}
+	}
+	for _, pattern := range m.include {
+		if pattern.Match(driver) {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty returns true if the matcher has no patterns, only exclusions or nothing at all
+func (m *DriverMatcher) Empty() bool {
+	return len(m.include) == 0
+}
+
+// Exclusions returns the exclusion entries of the matcher, with their ! prefix
+func (m *DriverMatcher) Exclusions() []string {
+	exclusions := make([]string, 0, len(m.exclude))
+	for _, entry := range m.entries {
+		if strings.HasPrefix(entry, "!") {
+			exclusions = append(exclusions, entry)
+		}
+	}
+	return exclusions
+}
+
+// String returns the entries of the matcher, comma separated
+func (m *DriverMatcher) String() string {
+	return strings.Join(m.entries, ",")
+}
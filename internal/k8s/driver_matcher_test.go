@@ -0,0 +1,126 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DriverPatterns(t *testing.T) {
+	tests := map[string]struct {
+		pattern  string
+		driver   string
+		expected bool
+	}{
+		"exact name":         {"csi-unity.dellemc.com", "csi-unity.dellemc.com", true},
+		"glob":               {"csi-vxflexos*.dellemc.com", "csi-vxflexos-prod.dellemc.com", true},
+		"glob mismatch":      {"csi-vxflexos*.dellemc.com", "csi-powerstore.dellemc.com", false},
+		"regular expression": {"/csi-(unity|powermax).*/", "csi-powermax-ns1.dellemc.com", true},
+		"whole name regex":   {"/unity/", "csi-unity.dellemc.com", false},
+		"surrounding spaces": {" csi-*.dellemc.com ", "csi-isilon.dellemc.com", true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pattern, err := k8s.ParseDriverPattern(tc.pattern)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, pattern.Match(tc.driver))
+		})
+	}
+
+	for _, pattern := range []string{"/csi-(/", "csi-[.dellemc.com", "/csi-[a-z]{1", "/"} {
+		_, err := k8s.ParseDriverPattern(pattern)
+		assert.Error(t, err)
+	}
+}
+
+func Test_DriverMatcher(t *testing.T) {
+	tests := map[string]struct {
+		entries  []string
+		matched  []string
+		rejected []string
+		empty    bool
+	}{
+		"exact names": {
+			entries:  []string{"csi-vxflexos.dellemc.com", "csi-powerstore.dellemc.com"},
+			matched:  []string{"csi-vxflexos.dellemc.com", "csi-powerstore.dellemc.com"},
+			rejected: []string{"csi-vxflexos-ns1.dellemc.com"},
+		},
+		"namespace suffixed instances": {
+			entries:  []string{"csi-*.dellemc.com", "!csi-*-test.dellemc.com"},
+			matched:  []string{"csi-vxflexos-ns1.dellemc.com", "csi-unity.dellemc.com"},
+			rejected: []string{"csi-vxflexos-test.dellemc.com", "ebs.csi.aws.com"},
+		},
+		"regular expression exclusion": {
+			entries:  []string{"/csi-(unity|isilon).*/", " !/.*-legacy\\..*/ "},
+			matched:  []string{"csi-isilon-prod.dellemc.com"},
+			rejected: []string{"csi-unity-legacy.dellemc.com", "csi-powermax.dellemc.com"},
+		},
+		"only exclusions": {
+			entries:  []string{"!csi-unity.dellemc.com", ""},
+			rejected: []string{"csi-unity.dellemc.com", "csi-isilon.dellemc.com"},
+			empty:    true,
+		},
+		"nothing": {
+			empty: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			matcher, err := k8s.NewDriverMatcher(tc.entries)
+			assert.NoError(t, err)
+			for _, driver := range tc.matched {
+				assert.True(t, matcher.Match(driver), driver)
+			}
+			for _, driver := range tc.rejected {
+				assert.False(t, matcher.Match(driver), driver)
+			}
+			assert.Equal(t, tc.empty, matcher.Empty())
+		})
+	}
+
+	matcher, err := k8s.NewDriverMatcher([]string{"csi-*.dellemc.com", "!csi-unity*", "!/.*-test/"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"!csi-unity*", "!/.*-test/"}, matcher.Exclusions())
+	assert.Equal(t, "csi-*.dellemc.com,!csi-unity*,!/.*-test/", matcher.String())
+
+	_, err = k8s.NewDriverMatcher([]string{"csi-*.dellemc.com", "!csi-["})
+	assert.Error(t, err)
+}
+
+func Test_SplitDriverPatterns(t *testing.T) {
+	tests := map[string]struct {
+		list     string
+		expected []string
+	}{
+		"names":                    {"csi-unity.dellemc.com, csi-isilon.dellemc.com", []string{"csi-unity.dellemc.com", "csi-isilon.dellemc.com"}},
+		"commas in expression":     {"/csi-[a-z]{1,2}\\.dellemc\\.com/,csi-unity*", []string{"/csi-[a-z]{1,2}\\.dellemc\\.com/", "csi-unity*"}},
+		"commas in exclusion":      {"csi-*, !/csi-x{1,2}/ ", []string{"csi-*", "!/csi-x{1,2}/ "}},
+		"slashes in expression":    {"/csi-a/b/,csi-c", []string{"/csi-a/b/", "csi-c"}},
+		"unterminated expression":  {"/csi-a,csi-b", []string{"/csi-a", "csi-b"}},
+		"expression ends the list": {"csi-a,/csi-(b|c)/", []string{"csi-a", "/csi-(b|c)/"}},
+		"empty list":               {"", nil},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, k8s.SplitDriverPatterns(tc.list))
+		})
+	}
+}
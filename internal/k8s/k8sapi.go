@@ -18,44 +18,618 @@ package k8s
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	storagev1 "k8s.io/api/storage/v1"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
+// DiscoveryInterval is how often the custom resources are looked up again to find whether they are installed
+var DiscoveryInterval = 5 * time.Minute
+
+// DiscoveryRetryInterval is how long a failed lookup of the custom resources waits before it is retried
+var DiscoveryRetryInterval = 10 * time.Second
+
 // API holds data used to access the K8S API
 type API struct {
 	Client kubernetes.Interface
 	Lock   sync.Mutex
+	// DynamicClient reads custom resources such as volume snapshots; they are not available when it is nil
+	DynamicClient dynamic.Interface
+	// ResyncPeriod is how often the informers replay their cached objects; zero disables resync
+	ResyncPeriod time.Duration
+	// Kubeconfig is the path of the kubeconfig file to connect with, or a list of paths separated like $KUBECONFIG;
+	// the in-cluster configuration is used when both Kubeconfig and Context are empty
+	Kubeconfig string
+	// Context is the kubeconfig context to connect to; the current context is used when it is empty
+	Context string
+	// Config is the configuration to connect with; it takes precedence over Kubeconfig and Context
+	Config *rest.Config
+	// VolumeEventHandler is notified of the changes of the persistent volumes when it is set
+	VolumeEventHandler cache.ResourceEventHandler
+	// WatchCSIDrivers starts the CSI driver informer with the topology informers, and HasSynced waits for it, for
+	// volume finders that discover their drivers; otherwise it is started when the CSI drivers are first read
+	WatchCSIDrivers bool
+
+	factory        informers.SharedInformerFactory
+	dynamicFactory dynamicinformer.DynamicSharedInformerFactory
+	stopCh         chan struct{}
+	synced         atomic.Bool
+	// done is closed when the context passed to Start is done; the informers are not started again after that
+	done <-chan struct{}
+	// started holds the names of the cached resources whose informer has been started
+	started map[string]bool
+	// listErrors holds the last list or watch error of each cached resource, indexed by resource name
+	listErrors sync.Map
+	// discovered holds the last lookup of the custom resources; nil until a custom resource is first read
+	discovered *atomic.Pointer[discoveryResult]
+}
+
+// discoveryResult is a lookup of the custom resources installed in the cluster
+type discoveryResult struct {
+	installed map[schema.GroupVersionResource]bool
+	// err is the error of the first lookup; later failures keep the previous result
+	err error
+}
+
+// ErrCacheNotSynced is wrapped by the errors returned for resources whose informer cache has not completed its initial
+// sync. Requests never wait for a cache: they fail right away until it has synced.
+var ErrCacheNotSynced = errors.New("cache has not synced")
+
+// ErrListDenied is wrapped by the errors returned for resources that cannot be listed because the API server forbids it,
+// e.g. when RBAC does not grant it, or does not serve them. The topology read from such resources is optional.
+var ErrListDenied = errors.New("cannot be listed")
+
+// errStopped is returned by requests made after the context passed to Start is done
+var errStopped = errors.New("the Kubernetes API client has been stopped")
+
+// cachedResource is a built-in resource served from a shared informer cache
+type cachedResource struct {
+	name        string
+	newInformer func(informers.SharedInformerFactory) cache.SharedIndexInformer
 }
 
-// GetPersistentVolumes will return a list of persistent volumes in the kubernetes cluster
+// Resources served from the shared informer factory
+var (
+	persistentVolumes = cachedResource{"persistent volumes", func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.Core().V1().PersistentVolumes().Informer()
+	}}
+	pods = cachedResource{"pods", func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.Core().V1().Pods().Informer()
+	}}
+	replicaSets = cachedResource{"replica sets", func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.Apps().V1().ReplicaSets().Informer()
+	}}
+	volumeAttachments = cachedResource{"volume attachments", func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.Storage().V1().VolumeAttachments().Informer()
+	}}
+	persistentVolumeClaims = cachedResource{"persistent volume claims", func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.Core().V1().PersistentVolumeClaims().Informer()
+	}}
+	storageClasses = cachedResource{"storage classes", func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.Storage().V1().StorageClasses().Informer()
+	}}
+	csiDrivers = cachedResource{"CSI drivers", func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.Storage().V1().CSIDrivers().Informer()
+	}}
+	claimEvents = cachedResource{"persistent volume claim events", func(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return factory.InformerFor(&corev1.Event{}, func(client kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+			return coreinformers.NewFilteredEventInformer(client, metav1.NamespaceAll, resync,
+				cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+				func(options *metav1.ListOptions) {
+					options.FieldSelector = "involvedObject.kind=PersistentVolumeClaim"
+				})
+		})
+	}}
+)
+
+// topologyResources are the resources the volume finder reads besides the persistent volumes. Their informers are
+// started with the persistent volume informer and the API has not synced until they have synced or are denied listing.
+var topologyResources = []cachedResource{pods, replicaSets, volumeAttachments, persistentVolumeClaims, storageClasses, claimEvents}
+
+// ErrResourceNotInstalled is returned for custom resources whose definition is not installed in the cluster
+var ErrResourceNotInstalled = errors.New("resource is not installed in the cluster")
+
+// Volume snapshot custom resources
+var (
+	VolumeSnapshotResource        = schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshots"}
+	VolumeSnapshotContentResource = schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshotcontents"}
+)
+
+// dynamicResources are the custom resources looked up by discoverCustomResources
+var dynamicResources = []schema.GroupVersionResource{VolumeSnapshotResource, VolumeSnapshotContentResource}
+
+// GetPersistentVolumes will return a list of persistent volumes in the kubernetes cluster, served from the informer cache.
+// It returns ErrCacheNotSynced until the cache has completed its initial sync rather than waiting for it.
 func (api *API) GetPersistentVolumes() (*corev1.PersistentVolumeList, error) {
+	objects, err := api.objects(persistentVolumes)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := &corev1.PersistentVolumeList{Items: make([]corev1.PersistentVolume, 0, len(objects))}
+	for _, obj := range objects {
+		if volume, ok := obj.(*corev1.PersistentVolume); ok {
+			volumes.Items = append(volumes.Items, *volume)
+		}
+	}
+	sort.Slice(volumes.Items, func(i, j int) bool {
+		return volumes.Items[i].Name < volumes.Items[j].Name
+	})
+	return volumes, nil
+}
+
+// GetPods will return a list of pods in all namespaces, served from the informer cache
+func (api *API) GetPods() (*corev1.PodList, error) {
+	objects, err := api.objects(pods)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := &corev1.PodList{Items: make([]corev1.Pod, 0, len(objects))}
+	for _, obj := range objects {
+		if pod, ok := obj.(*corev1.Pod); ok {
+			pods.Items = append(pods.Items, *pod)
+		}
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Namespace+"/"+pods.Items[i].Name < pods.Items[j].Namespace+"/"+pods.Items[j].Name
+	})
+	return pods, nil
+}
+
+// GetReplicaSets will return a list of replica sets in all namespaces, served from the informer cache
+func (api *API) GetReplicaSets() (*appsv1.ReplicaSetList, error) {
+	objects, err := api.objects(replicaSets)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaSets := &appsv1.ReplicaSetList{Items: make([]appsv1.ReplicaSet, 0, len(objects))}
+	for _, obj := range objects {
+		if replicaSet, ok := obj.(*appsv1.ReplicaSet); ok {
+			replicaSets.Items = append(replicaSets.Items, *replicaSet)
+		}
+	}
+	return replicaSets, nil
+}
+
+// GetVolumeAttachments will return a list of volume attachments in the kubernetes cluster, served from the informer cache
+func (api *API) GetVolumeAttachments() (*storagev1.VolumeAttachmentList, error) {
+	objects, err := api.objects(volumeAttachments)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := &storagev1.VolumeAttachmentList{Items: make([]storagev1.VolumeAttachment, 0, len(objects))}
+	for _, obj := range objects {
+		if attachment, ok := obj.(*storagev1.VolumeAttachment); ok {
+			attachments.Items = append(attachments.Items, *attachment)
+		}
+	}
+	sort.Slice(attachments.Items, func(i, j int) bool {
+		return attachments.Items[i].Spec.NodeName < attachments.Items[j].Spec.NodeName
+	})
+	return attachments, nil
+}
+
+// GetPersistentVolumeClaims will return a list of persistent volume claims in all namespaces, served from the informer cache
+func (api *API) GetPersistentVolumeClaims() (*corev1.PersistentVolumeClaimList, error) {
+	objects, err := api.objects(persistentVolumeClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &corev1.PersistentVolumeClaimList{Items: make([]corev1.PersistentVolumeClaim, 0, len(objects))}
+	for _, obj := range objects {
+		if claim, ok := obj.(*corev1.PersistentVolumeClaim); ok {
+			claims.Items = append(claims.Items, *claim)
+		}
+	}
+	sort.Slice(claims.Items, func(i, j int) bool {
+		return claims.Items[i].Namespace+"/"+claims.Items[i].Name < claims.Items[j].Namespace+"/"+claims.Items[j].Name
+	})
+	return claims, nil
+}
+
+// GetStorageClasses will return a list of storage classes in the kubernetes cluster, served from the informer cache
+func (api *API) GetStorageClasses() (*storagev1.StorageClassList, error) {
+	objects, err := api.objects(storageClasses)
+	if err != nil {
+		return nil, err
+	}
+
+	classes := &storagev1.StorageClassList{Items: make([]storagev1.StorageClass, 0, len(objects))}
+	for _, obj := range objects {
+		if class, ok := obj.(*storagev1.StorageClass); ok {
+			classes.Items = append(classes.Items, *class)
+		}
+	}
+	return classes, nil
+}
+
+// GetCSIDrivers will return a list of the CSI drivers installed in the kubernetes cluster, sorted by name and served
+// from the informer cache
+func (api *API) GetCSIDrivers() (*storagev1.CSIDriverList, error) {
+	objects, err := api.objects(csiDrivers)
+	if err != nil {
+		return nil, err
+	}
+
+	drivers := &storagev1.CSIDriverList{Items: make([]storagev1.CSIDriver, 0, len(objects))}
+	for _, obj := range objects {
+		if driver, ok := obj.(*storagev1.CSIDriver); ok {
+			drivers.Items = append(drivers.Items, *driver)
+		}
+	}
+	sort.Slice(drivers.Items, func(i, j int) bool { return drivers.Items[i].Name < drivers.Items[j].Name })
+	return drivers, nil
+}
+
+// GetPersistentVolumeClaimEvents will return a list of the events about persistent volume claims in all namespaces,
+// served from the informer cache
+func (api *API) GetPersistentVolumeClaimEvents() (*corev1.EventList, error) {
+	objects, err := api.objects(claimEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	events := &corev1.EventList{Items: make([]corev1.Event, 0, len(objects))}
+	for _, obj := range objects {
+		if event, ok := obj.(*corev1.Event); ok && event.InvolvedObject.Kind == "PersistentVolumeClaim" {
+			events.Items = append(events.Items, *event)
+		}
+	}
+	return events, nil
+}
+
+// GetVolumeSnapshots will return a list of volume snapshots in all namespaces, served from the informer cache.
+// It returns ErrResourceNotInstalled when the snapshot custom resources are not installed.
+func (api *API) GetVolumeSnapshots() (*unstructured.UnstructuredList, error) {
+	return api.customResources(VolumeSnapshotResource)
+}
+
+// GetVolumeSnapshotContents will return a list of volume snapshot contents, served from the informer cache.
+// It returns ErrResourceNotInstalled when the snapshot custom resources are not installed.
+func (api *API) GetVolumeSnapshotContents() (*unstructured.UnstructuredList, error) {
+	return api.customResources(VolumeSnapshotContentResource)
+}
+
+// customResources returns the cached custom resources of gvr, sorted by namespace and name
+func (api *API) customResources(gvr schema.GroupVersionResource) (*unstructured.UnstructuredList, error) {
+	informer, err := api.dynamicInformer(gvr)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := informer.GetStore().List()
+	list := &unstructured.UnstructuredList{Items: make([]unstructured.Unstructured, 0, len(objects))}
+	for _, obj := range objects {
+		if object, ok := obj.(*unstructured.Unstructured); ok {
+			list.Items = append(list.Items, *object)
+		}
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].GetNamespace()+"/"+list.Items[i].GetName() < list.Items[j].GetNamespace()+"/"+list.Items[j].GetName()
+	})
+	return list, nil
+}
+
+// Start connects to the k8s API and starts the informers of the persistent volumes and of the topology resources without
+// waiting for them to sync. The informers of the other resources are started when they are first read. The informers are stopped when ctx is done, including
+// those started again after a Stop, and are not started again after that.
+func (api *API) Start(ctx context.Context) error {
+	api.Lock.Lock()
+	defer api.Lock.Unlock()
+	api.done = ctx.Done()
+	return api.startFactory()
+}
+
+// Stop stops the running informers; they are started again on the next request
+func (api *API) Stop() {
+	api.Lock.Lock()
+	defer api.Lock.Unlock()
+	api.stop()
+}
+
+// stopWhenDone stops the informers of stopCh once done is closed; it returns early when they are stopped by Stop
+func (api *API) stopWhenDone(done <-chan struct{}, stopCh chan struct{}) {
+	select {
+	case <-done:
+		api.Lock.Lock()
+		defer api.Lock.Unlock()
+		if api.stopCh == stopCh {
+			api.stop()
+		}
+	case <-stopCh:
+	}
+}
+
+// stop stops the running informers. The caller must hold api.Lock.
+func (api *API) stop() {
+	if api.stopCh != nil {
+		close(api.stopCh)
+	}
+	api.factory = nil
+	api.dynamicFactory = nil
+	api.started = nil
+	api.discovered = nil
+	api.stopCh = nil
+	api.synced.Store(false)
+	api.listErrors.Clear()
+}
+
+// HasSynced returns true once the persistent volume informer has completed its initial sync and the informers of the
+// topology resources, and of the CSI drivers when WatchCSIDrivers is set, have either completed theirs or been denied
+// listing their resource
+func (api *API) HasSynced() bool {
+	return api.synced.Load()
+}
+
+// SyncFailed returns true while the persistent volume informer has not completed its initial sync and its last attempt
+// to connect or to list the persistent volumes failed
+func (api *API) SyncFailed() bool {
+	_, failed := api.listErrors.Load(persistentVolumes.name)
+	return failed && !api.synced.Load()
+}
+
+// objects returns the cached objects of a resource. It does not wait for the cache: until the informer has completed
+// its initial sync, an error wrapping ErrCacheNotSynced and the last list error of the informer is returned.
+func (api *API) objects(resource cachedResource) ([]interface{}, error) {
+	informer, err := api.informer(resource)
+	if err != nil {
+		return nil, err
+	}
+	if !informer.HasSynced() {
+		if listErr, ok := api.listErrors.Load(resource.name); ok {
+			if listDenied(listErr.(error)) {
+				return nil, fmt.Errorf("%s %w: %v", resource.name, ErrListDenied, listErr)
+			}
+			return nil, fmt.Errorf("%s %w: %v", resource.name, ErrCacheNotSynced, listErr)
+		}
+		return nil, fmt.Errorf("%s %w", resource.name, ErrCacheNotSynced)
+	}
+	return informer.GetStore().List(), nil
+}
+
+// informer returns the shared informer of a resource, starting the factory and the informer if needed. api.Lock is only
+// held to look up the informer, so that readers of the caches never wait for each other.
+func (api *API) informer(resource cachedResource) (cache.SharedIndexInformer, error) {
 	api.Lock.Lock()
 	defer api.Lock.Unlock()
+	if err := api.startFactory(); err != nil {
+		return nil, err
+	}
+	return api.startInformer(resource)
+}
+
+// settled returns a function reporting whether the informer of a resource has completed its initial sync or has been
+// denied listing the resource
+func (api *API) settled(resource cachedResource, informer cache.SharedIndexInformer) cache.InformerSynced {
+	return func() bool {
+		if informer.HasSynced() {
+			return true
+		}
+		listErr, ok := api.listErrors.Load(resource.name)
+		return ok && listDenied(listErr.(error))
+	}
+}
+
+// listDenied returns true if a list error means that the resource cannot be listed rather than a transient failure
+func listDenied(err error) bool {
+	return apierrors.IsForbidden(err) || apierrors.IsNotFound(err)
+}
+
+// startInformer returns the shared informer of a resource, starting it on its first use so that the resources of
+// features that are never used are neither cached nor listed. Informers that fail to list their resource, e.g. because
+// RBAC forbids it, retry with the exponential backoff of their reflector; their last error is kept in api.listErrors to
+// explain why the resource is unavailable. The caller must hold api.Lock.
+func (api *API) startInformer(resource cachedResource) (cache.SharedIndexInformer, error) {
+	informer := resource.newInformer(api.factory)
+	if api.started[resource.name] {
+		return informer, nil
+	}
+	name := resource.name
+	err := informer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+		api.listErrors.Store(name, err)
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	api.factory.Start(api.stopCh)
+	api.started[name] = true
+	return informer, nil
+}
+
+// dynamicInformer returns the informer of the custom resource gvr, starting it if needed. It does not wait for the
+// cache and does not look up the resource: it reads the result of the last lookup made by discoverCustomResources, which
+// is started by the first read of a custom resource.
+func (api *API) dynamicInformer(gvr schema.GroupVersionResource) (cache.SharedIndexInformer, error) {
+	api.Lock.Lock()
+	err := api.startFactory()
+	if err == nil && api.dynamicFactory != nil && api.discovered == nil {
+		api.discovered = &atomic.Pointer[discoveryResult]{}
+		go discoverCustomResources(api.Client.Discovery(), api.discovered, api.stopCh)
+	}
+	factory, discovered, stopCh := api.dynamicFactory, api.discovered, api.stopCh
+	api.Lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if factory == nil {
+		return nil, errors.New("no dynamic client to read custom resources")
+	}
+
+	result := discovered.Load()
+	switch {
+	case result == nil:
+		return nil, fmt.Errorf("%s %w: the resource has not been looked up yet", gvr.Resource, ErrCacheNotSynced)
+	case result.err != nil:
+		return nil, fmt.Errorf("looking up %s: %v", gvr.Resource, result.err)
+	case !result.installed[gvr]:
+		return nil, ErrResourceNotInstalled
+	}
+
+	informer := factory.ForResource(gvr).Informer()
+	factory.Start(stopCh)
+	if !informer.HasSynced() {
+		return nil, fmt.Errorf("%s %w", gvr.Resource, ErrCacheNotSynced)
+	}
+	return informer, nil
+}
+
+// discoverCustomResources looks up which custom resources are installed, then looks them up again every
+// DiscoveryInterval, or after DiscoveryRetryInterval when the lookup fails, until stopCh is closed. A failed lookup keeps
+// the result of the previous one.
+func discoverCustomResources(client discovery.DiscoveryInterface, discovered *atomic.Pointer[discoveryResult], stopCh chan struct{}) {
+	for {
+		interval := DiscoveryInterval
+		installed, err := installedResources(client, dynamicResources)
+		if err != nil {
+			interval = DiscoveryRetryInterval
+			if discovered.Load() == nil {
+				discovered.Store(&discoveryResult{err: err})
+			}
+		} else {
+			discovered.Store(&discoveryResult{installed: installed})
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// installedResources returns which of the resources are installed in the cluster
+func installedResources(client discovery.DiscoveryInterface, resources []schema.GroupVersionResource) (map[schema.GroupVersionResource]bool, error) {
+	installed := make(map[schema.GroupVersionResource]bool, len(resources))
+	groupVersions := make(map[string]*metav1.APIResourceList)
+	for _, gvr := range resources {
+		groupVersion := gvr.GroupVersion().String()
+		list, ok := groupVersions[groupVersion]
+		if !ok {
+			var err error
+			list, err = client.ServerResourcesForGroupVersion(groupVersion)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			groupVersions[groupVersion] = list
+		}
+		if list == nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			installed[gvr] = installed[gvr] || resource.Name == gvr.Resource
+		}
+	}
+	return installed, nil
+}
+
+// startFactory connects the client and starts the shared informer factory with the persistent volume informer, which
+// the service cannot work without, and the informers of the topology resources. The caller must hold api.Lock.
+func (api *API) startFactory() error {
+	if api.factory != nil {
+		return nil
+	}
+	if api.done != nil {
+		select {
+		case <-api.done:
+			return errStopped
+		default:
+		}
+	}
 	if api.Client == nil {
 		err := ConnectFn(api)
 		if err != nil {
-			return nil, err
+			api.listErrors.Store(persistentVolumes.name, err)
+			return err
+		}
+	}
+
+	factory := informers.NewSharedInformerFactory(api.Client, api.ResyncPeriod)
+	volumeInformer := persistentVolumes.newInformer(factory)
+	if api.VolumeEventHandler != nil {
+		if _, err := volumeInformer.AddEventHandler(api.VolumeEventHandler); err != nil {
+			return err
+		}
+	}
+
+	api.factory = factory
+	api.stopCh = make(chan struct{})
+	api.started = make(map[string]bool)
+	if _, err := api.startInformer(persistentVolumes); err != nil {
+		api.stop()
+		return err
+	}
+	resources := topologyResources
+	if api.WatchCSIDrivers {
+		resources = append(resources[:len(resources):len(resources)], csiDrivers)
+	}
+	synced := []cache.InformerSynced{volumeInformer.HasSynced}
+	for _, resource := range resources {
+		informer, err := api.startInformer(resource)
+		if err != nil {
+			api.stop()
+			return err
+		}
+		synced = append(synced, api.settled(resource, informer))
+	}
+	stopCh := api.stopCh
+	go func() {
+		if cache.WaitForCacheSync(stopCh, synced...) {
+			api.synced.Store(true)
 		}
+	}()
+	if api.done != nil {
+		go api.stopWhenDone(api.done, stopCh)
 	}
-	return api.Client.CoreV1().PersistentVolumes().List(context.Background(), metav1.ListOptions{})
+
+	if api.DynamicClient != nil {
+		api.dynamicFactory = dynamicinformer.NewDynamicSharedInformerFactory(api.DynamicClient, api.ResyncPeriod)
+	}
+	return nil
 }
 
-// ConnectFn will connect the client to the k8s API
+// ConnectFn will connect the client to the k8s API. The clients are only set when both can be created, so a failed
+// connection is retried in full.
 var ConnectFn = func(api *API) error {
-	config, err := getConfig()
+	config, err := getConfig(api)
 	if err != nil {
 		return err
 	}
-	api.Client, err = NewConfigFn(config)
+	client, err := NewConfigFn(config)
 	if err != nil {
 		return err
 	}
+	dynamicClient, err := NewDynamicConfigFn(config)
+	if err != nil {
+		return err
+	}
+	api.Client, api.DynamicClient = client, dynamicClient
 	return nil
 }
 
@@ -69,7 +643,35 @@ var NewConfigFn = func(config *rest.Config) (*kubernetes.Clientset, error) {
 	return kubernetes.NewForConfig(config)
 }
 
-func getConfig() (*rest.Config, error) {
+// NewDynamicConfigFn will return a valid dynamic.Interface
+var NewDynamicConfigFn = func(config *rest.Config) (dynamic.Interface, error) {
+	return dynamic.NewForConfig(config)
+}
+
+// KubeconfigFn will return the configuration of a kubeconfig context. The default kubeconfig loading rules apply when
+// kubeconfig is empty and the current context is used when contextName is empty.
+var KubeconfigFn = func(kubeconfig, contextName string) (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules = &clientcmd.ClientConfigLoadingRules{Precedence: filepath.SplitList(kubeconfig)}
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+}
+
+// getConfig returns the configuration of the API, the configuration of its kubeconfig context, or the in-cluster
+// configuration when neither is set
+func getConfig(api *API) (*rest.Config, error) {
+	if api.Config != nil {
+		return api.Config, nil
+	}
+	if api.Kubeconfig != "" || api.Context != "" {
+		config, err := KubeconfigFn(api.Kubeconfig, api.Context)
+		if err != nil {
+			return nil, fmt.Errorf("loading kubeconfig: %v", err)
+		}
+		return config, nil
+	}
 	config, err := InClusterConfigFn()
 	if err != nil {
 		return nil, err
@@ -17,19 +17,35 @@
 package k8s_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/dell/karavi-topology/internal/k8s"
 
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
 )
 
 func Test_GetPersistentVolumes(t *testing.T) {
@@ -100,6 +116,11 @@ func Test_GetPersistentVolumes(t *testing.T) {
 				defer func() { k8s.InClusterConfigFn = oldInClusterConfig }()
 				k8s.InClusterConfigFn = inClusterConfig
 			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if k8sclient.Start(ctx) == nil {
+				assert.Eventually(t, k8sclient.HasSynced, 5*time.Second, 10*time.Millisecond)
+			}
 			volumes, err := k8sclient.GetPersistentVolumes()
 			for _, checkFn := range checkFns {
 				checkFn(t, volumes, err)
@@ -115,6 +136,93 @@ func Test_InClusterConfigFn(t *testing.T) {
 	})
 }
 
+func Test_KubeconfigFn(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: local
+  cluster:
+    server: https://127.0.0.1:6443
+- name: remote
+  cluster:
+    server: https://10.0.0.1:6443
+contexts:
+- name: local
+  context:
+    cluster: local
+    user: admin
+- name: remote
+  context:
+    cluster: remote
+    user: admin
+current-context: local
+users:
+- name: admin
+  user:
+    token: token
+`), 0o600))
+
+	config, err := k8s.KubeconfigFn(kubeconfig, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://127.0.0.1:6443", config.Host)
+
+	config, err = k8s.KubeconfigFn(kubeconfig, "remote")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://10.0.0.1:6443", config.Host)
+
+	_, err = k8s.KubeconfigFn(kubeconfig, "missing")
+	assert.Error(t, err)
+}
+
+func Test_ConnectWithKubeconfig(t *testing.T) {
+	tests := map[string]struct {
+		api                *k8s.API
+		expectedKubeconfig string
+		expectedContext    string
+		inCluster          bool
+	}{
+		"kubeconfig and context": {
+			api:                &k8s.API{Kubeconfig: "/home/user/.kube/config", Context: "remote"},
+			expectedKubeconfig: "/home/user/.kube/config",
+			expectedContext:    "remote",
+		},
+		"context only": {
+			api:             &k8s.API{Context: "remote"},
+			expectedContext: "remote",
+		},
+		"in cluster": {
+			api:       &k8s.API{},
+			inCluster: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var kubeconfig, contextName string
+			inCluster := false
+
+			oldKubeconfigFn := k8s.KubeconfigFn
+			defer func() { k8s.KubeconfigFn = oldKubeconfigFn }()
+			k8s.KubeconfigFn = func(path, name string) (*rest.Config, error) {
+				kubeconfig, contextName = path, name
+				return nil, errors.New("error")
+			}
+			oldInClusterConfigFn := k8s.InClusterConfigFn
+			defer func() { k8s.InClusterConfigFn = oldInClusterConfigFn }()
+			k8s.InClusterConfigFn = func() (*rest.Config, error) {
+				inCluster = true
+				return nil, errors.New("error")
+			}
+
+			_, err := tc.api.GetPersistentVolumes()
+			assert.Error(t, err)
+			assert.Equal(t, tc.expectedKubeconfig, kubeconfig)
+			assert.Equal(t, tc.expectedContext, contextName)
+			assert.Equal(t, tc.inCluster, inCluster)
+		})
+	}
+}
+
 func Test_NewForConfigError(t *testing.T) {
 	k8sapi := &k8s.API{}
 
@@ -137,3 +245,432 @@ func Test_NewForConfigError(t *testing.T) {
 		assert.Equal(t, expected, err.Error())
 	}
 }
+
+func Test_StartAndHasSynced(t *testing.T) {
+	volumes := &corev1.PersistentVolumeList{
+		Items: []corev1.PersistentVolume{
+			{ObjectMeta: metav1.ObjectMeta{Name: "pv-2"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}},
+		},
+	}
+	oldConnectFn := k8s.ConnectFn
+	defer func() { k8s.ConnectFn = oldConnectFn }()
+	k8s.ConnectFn = func(api *k8s.API) error {
+		api.Client = fake.NewSimpleClientset(volumes)
+		return nil
+	}
+
+	api := &k8s.API{}
+	assert.False(t, api.HasSynced())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, api.Start(ctx))
+	assert.Eventually(t, api.HasSynced, 5*time.Second, 10*time.Millisecond)
+
+	result, err := api.GetPersistentVolumes()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Items))
+	assert.Equal(t, "pv-1", result.Items[0].Name)
+	assert.Equal(t, "pv-2", result.Items[1].Name)
+
+	cancel()
+	assert.Eventually(t, func() bool { return !api.HasSynced() }, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_RestartedInformersStopWithContext(t *testing.T) {
+	oldConnectFn := k8s.ConnectFn
+	defer func() { k8s.ConnectFn = oldConnectFn }()
+	k8s.ConnectFn = func(api *k8s.API) error {
+		api.Client = fake.NewSimpleClientset(&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}})
+		return nil
+	}
+
+	api := &k8s.API{}
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, api.Start(ctx))
+	assert.Eventually(t, api.HasSynced, 5*time.Second, 10*time.Millisecond)
+
+	// informers stopped by Stop are started again by the next request
+	api.Stop()
+	assert.False(t, api.HasSynced())
+	volumes, err := cached(t, api.GetPersistentVolumes)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(volumes.Items))
+	assert.Eventually(t, api.HasSynced, 5*time.Second, 10*time.Millisecond)
+
+	// and stopped for good once the context is done
+	cancel()
+	assert.Eventually(t, func() bool { return !api.HasSynced() }, 5*time.Second, 10*time.Millisecond)
+	_, err = api.GetPersistentVolumes()
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, k8s.ErrCacheNotSynced)
+}
+
+func Test_ConnectIsAllOrNothing(t *testing.T) {
+	oldInClusterConfigFn := k8s.InClusterConfigFn
+	defer func() { k8s.InClusterConfigFn = oldInClusterConfigFn }()
+	k8s.InClusterConfigFn = func() (*rest.Config, error) {
+		return &rest.Config{Host: "https://127.0.0.1:6443"}, nil
+	}
+	oldNewDynamicConfigFn := k8s.NewDynamicConfigFn
+	defer func() { k8s.NewDynamicConfigFn = oldNewDynamicConfigFn }()
+	k8s.NewDynamicConfigFn = func(_ *rest.Config) (dynamic.Interface, error) {
+		return nil, errors.New("could not create dynamic client")
+	}
+
+	api := &k8s.API{}
+	assert.Error(t, k8s.ConnectFn(api))
+	assert.Nil(t, api.Client)
+	assert.Nil(t, api.DynamicClient)
+
+	k8s.NewDynamicConfigFn = oldNewDynamicConfigFn
+	assert.NoError(t, k8s.ConnectFn(api))
+	assert.NotNil(t, api.Client)
+	assert.NotNil(t, api.DynamicClient)
+}
+
+func Test_GetPersistentVolumesNotSynced(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "persistentvolumes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+	api := &k8s.API{Client: client}
+	defer api.Stop()
+
+	_, err := api.GetPersistentVolumes()
+	assert.ErrorIs(t, err, k8s.ErrCacheNotSynced)
+	assert.False(t, api.HasSynced())
+	assert.Eventually(t, api.SyncFailed, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_SyncFailedOnConnectError(t *testing.T) {
+	oldConnectFn := k8s.ConnectFn
+	defer func() { k8s.ConnectFn = oldConnectFn }()
+	k8s.ConnectFn = func(api *k8s.API) error {
+		return errors.New("connection refused")
+	}
+
+	api := &k8s.API{}
+	assert.False(t, api.SyncFailed())
+	assert.Error(t, api.Start(context.Background()))
+	assert.True(t, api.SyncFailed())
+	assert.False(t, api.HasSynced())
+}
+
+func Test_TopologyCachesDenied(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}})
+	client.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("pods is forbidden"))
+	})
+	oldConnectFn := k8s.ConnectFn
+	defer func() { k8s.ConnectFn = oldConnectFn }()
+	k8s.ConnectFn = func(api *k8s.API) error {
+		api.Client = client
+		return nil
+	}
+
+	api := &k8s.API{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, api.Start(ctx))
+	// the API has synced once the pods are known to be forbidden
+	assert.Eventually(t, api.HasSynced, 5*time.Second, 10*time.Millisecond)
+	listed := make(map[string]bool)
+	for _, action := range client.Actions() {
+		listed[action.GetResource().Resource] = true
+	}
+	for _, resource := range []string{"persistentvolumes", "pods", "replicasets", "volumeattachments", "persistentvolumeclaims", "storageclasses", "events"} {
+		assert.True(t, listed[resource], "%s are listed on start", resource)
+	}
+	assert.False(t, listed["csidrivers"], "CSI drivers are listed on first read")
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := api.GetPods()
+			assert.ErrorIs(t, err, k8s.ErrListDenied)
+			assert.Contains(t, err.Error(), "pods is forbidden")
+			volumes, err := api.GetPersistentVolumes()
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(volumes.Items))
+		}()
+	}
+	wg.Wait()
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func Test_StartWatchingCSIDrivers(t *testing.T) {
+	client := fake.NewSimpleClientset(&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "csi-unity.dellemc.com"}})
+	oldConnectFn := k8s.ConnectFn
+	defer func() { k8s.ConnectFn = oldConnectFn }()
+	k8s.ConnectFn = func(api *k8s.API) error {
+		api.Client = client
+		return nil
+	}
+
+	api := &k8s.API{WatchCSIDrivers: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, api.Start(ctx))
+	assert.Eventually(t, api.HasSynced, 5*time.Second, 10*time.Millisecond)
+
+	// the CSI drivers have synced with the other caches, so the first read is served from the cache
+	drivers, err := api.GetCSIDrivers()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(drivers.Items))
+}
+
+func Test_TopologyCachesNotSynced(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}})
+	client.PrependReactor("list", "volumeattachments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection reset by peer")
+	})
+	api := &k8s.API{Client: client}
+	defer api.Stop()
+
+	_, err := cached(t, api.GetPersistentVolumes)
+	assert.NoError(t, err)
+	// a failure to list that may be transient keeps the API from syncing
+	assert.Never(t, api.HasSynced, 500*time.Millisecond, 10*time.Millisecond)
+	_, err = api.GetVolumeAttachments()
+	assert.ErrorIs(t, err, k8s.ErrCacheNotSynced)
+}
+
+// cached retries get until the cache it reads has synced
+func cached[T any](t *testing.T, get func() (T, error)) (T, error) {
+	var result T
+	var err error
+	assert.Eventually(t, func() bool {
+		result, err = get()
+		return !errors.Is(err, k8s.ErrCacheNotSynced)
+	}, 5*time.Second, 10*time.Millisecond)
+	return result, err
+}
+
+func Test_StartError(t *testing.T) {
+	oldConnectFn := k8s.ConnectFn
+	defer func() { k8s.ConnectFn = oldConnectFn }()
+	k8s.ConnectFn = func(_ *k8s.API) error {
+		return errors.New("error")
+	}
+
+	api := &k8s.API{}
+	assert.Error(t, api.Start(context.Background()))
+	assert.False(t, api.HasSynced())
+}
+
+func Test_GetPodsAndReplicaSets(t *testing.T) {
+	api := &k8s.API{
+		Client: fake.NewSimpleClientset(
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2", Namespace: "ns-1"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "ns-2"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "ns-1"}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "rs-1", Namespace: "ns-1"}},
+		),
+	}
+	defer api.Stop()
+
+	pods, err := cached(t, api.GetPods)
+	assert.NoError(t, err)
+	names := make([]string, 0)
+	for _, pod := range pods.Items {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	assert.Equal(t, []string{"ns-1/pod-1", "ns-1/pod-2", "ns-2/pod-1"}, names)
+
+	replicaSets, err := cached(t, api.GetReplicaSets)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(replicaSets.Items))
+	assert.Equal(t, "rs-1", replicaSets.Items[0].Name)
+}
+
+func Test_GetVolumeAttachments(t *testing.T) {
+	pv := "pv-1"
+	api := &k8s.API{
+		Client: fake.NewSimpleClientset(
+			&storagev1.VolumeAttachment{
+				ObjectMeta: metav1.ObjectMeta{Name: "csi-2"},
+				Spec:       storagev1.VolumeAttachmentSpec{NodeName: "node-2", Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: &pv}},
+			},
+			&storagev1.VolumeAttachment{
+				ObjectMeta: metav1.ObjectMeta{Name: "csi-1"},
+				Spec:       storagev1.VolumeAttachmentSpec{NodeName: "node-1", Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: &pv}},
+			},
+		),
+	}
+	defer api.Stop()
+
+	attachments, err := cached(t, api.GetVolumeAttachments)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(attachments.Items))
+	assert.Equal(t, "node-1", attachments.Items[0].Spec.NodeName)
+	assert.Equal(t, "node-2", attachments.Items[1].Spec.NodeName)
+}
+
+func Test_GetPersistentVolumeClaimsStorageClassesAndEvents(t *testing.T) {
+	api := &k8s.API{
+		Client: fake.NewSimpleClientset(
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc-2", Namespace: "ns-1"}},
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc-1", Namespace: "ns-1"}},
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "powerstore"}, Provisioner: "csi-powerstore.dellemc.com"},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "pvc-1.1", Namespace: "ns-1"},
+				InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", Name: "pvc-1"},
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "pod-1.1", Namespace: "ns-1"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "pod-1"},
+			},
+		),
+	}
+	defer api.Stop()
+
+	claims, err := cached(t, api.GetPersistentVolumeClaims)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(claims.Items))
+	assert.Equal(t, "pvc-1", claims.Items[0].Name)
+
+	classes, err := cached(t, api.GetStorageClasses)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(classes.Items))
+	assert.Equal(t, "csi-powerstore.dellemc.com", classes.Items[0].Provisioner)
+
+	events, err := cached(t, api.GetPersistentVolumeClaimEvents)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events.Items))
+	assert.Equal(t, "pvc-1", events.Items[0].InvolvedObject.Name)
+}
+
+func Test_GetCSIDrivers(t *testing.T) {
+	api := &k8s.API{
+		Client: fake.NewSimpleClientset(
+			&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "csi-vxflexos.dellemc.com"}},
+			&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "csi-powerstore.dellemc.com"}},
+		),
+	}
+	defer api.Stop()
+
+	drivers, err := cached(t, api.GetCSIDrivers)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(drivers.Items))
+	assert.Equal(t, "csi-powerstore.dellemc.com", drivers.Items[0].Name)
+}
+
+func Test_GetVolumeSnapshotResources(t *testing.T) {
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "snapshot.storage.k8s.io/v1",
+		"kind":       "VolumeSnapshot",
+		"metadata":   map[string]interface{}{"name": "snap-1", "namespace": "ns-1"},
+	}}
+	content := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "snapshot.storage.k8s.io/v1",
+		"kind":       "VolumeSnapshotContent",
+		"metadata":   map[string]interface{}{"name": "snapcontent-1"},
+	}}
+	newDynamicClient := func() *dynamicfake.FakeDynamicClient {
+		return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			k8s.VolumeSnapshotResource:        "VolumeSnapshotList",
+			k8s.VolumeSnapshotContentResource: "VolumeSnapshotContentList",
+		}, snapshot, content)
+	}
+
+	t.Run("installed", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		client.Resources = []*metav1.APIResourceList{{
+			GroupVersion: "snapshot.storage.k8s.io/v1",
+			APIResources: []metav1.APIResource{{Name: "volumesnapshots"}, {Name: "volumesnapshotcontents"}},
+		}}
+		api := &k8s.API{Client: client, DynamicClient: newDynamicClient()}
+		defer api.Stop()
+
+		snapshots, err := cached(t, api.GetVolumeSnapshots)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(snapshots.Items))
+		assert.Equal(t, "snap-1", snapshots.Items[0].GetName())
+
+		contents, err := cached(t, api.GetVolumeSnapshotContents)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(contents.Items))
+		assert.Equal(t, "snapcontent-1", contents.Items[0].GetName())
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		api := &k8s.API{Client: fake.NewSimpleClientset(), DynamicClient: newDynamicClient()}
+		defer api.Stop()
+
+		_, err := cached(t, api.GetVolumeSnapshots)
+		assert.ErrorIs(t, err, k8s.ErrResourceNotInstalled)
+	})
+
+	t.Run("no dynamic client", func(t *testing.T) {
+		api := &k8s.API{Client: fake.NewSimpleClientset()}
+		defer api.Stop()
+
+		_, err := cached(t, api.GetVolumeSnapshotContents)
+		assert.Error(t, err)
+	})
+}
+
+func Test_CustomResourceDiscoveryIsCached(t *testing.T) {
+	oldInterval := k8s.DiscoveryInterval
+	defer func() { k8s.DiscoveryInterval = oldInterval }()
+	k8s.DiscoveryInterval = 200 * time.Millisecond
+
+	client := fake.NewSimpleClientset()
+	lookups := atomic.Int32{}
+	client.PrependReactor("get", "resource", func(k8stesting.Action) (bool, runtime.Object, error) {
+		lookups.Add(1)
+		return false, nil, nil
+	})
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		k8s.VolumeSnapshotResource:        "VolumeSnapshotList",
+		k8s.VolumeSnapshotContentResource: "VolumeSnapshotContentList",
+	})
+	api := &k8s.API{Client: client, DynamicClient: dynamicClient}
+	defer api.Stop()
+
+	_, err := cached(t, api.GetVolumeSnapshots)
+	assert.ErrorIs(t, err, k8s.ErrResourceNotInstalled)
+	for i := 0; i < 10; i++ {
+		_, err = api.GetVolumeSnapshotContents()
+		assert.ErrorIs(t, err, k8s.ErrResourceNotInstalled)
+	}
+	// both resources share a group version, looked up once until the interval elapses
+	assert.Equal(t, int32(1), lookups.Load())
+	assert.Eventually(t, func() bool { return lookups.Load() > 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_StartWithVolumeEventHandler(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}})
+	oldConnectFn := k8s.ConnectFn
+	defer func() { k8s.ConnectFn = oldConnectFn }()
+	k8s.ConnectFn = func(api *k8s.API) error {
+		api.Client = client
+		return nil
+	}
+
+	added := make(chan string, 2)
+	api := &k8s.API{VolumeEventHandler: cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				added <- obj.(*corev1.PersistentVolume).Name
+			}
+		},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, api.Start(ctx))
+	assert.Eventually(t, api.HasSynced, 5*time.Second, 10*time.Millisecond)
+
+	_, err := client.CoreV1().PersistentVolumes().Create(ctx, &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-2"}}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	select {
+	case name := <-added:
+		assert.Equal(t, "pv-2", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the volume event")
+	}
+}
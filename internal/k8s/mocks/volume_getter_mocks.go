@@ -24,7 +24,10 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
-	v1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/apps/v1"
+	v10 "k8s.io/api/core/v1"
+	v11 "k8s.io/api/storage/v1"
+	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // MockVolumeGetter is a mock of VolumeGetter interface.
@@ -50,11 +53,56 @@ func (m *MockVolumeGetter) EXPECT() *MockVolumeGetterMockRecorder {
 	return m.recorder
 }
 
+// GetCSIDrivers mocks base method.
+func (m *MockVolumeGetter) GetCSIDrivers() (*v11.CSIDriverList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCSIDrivers")
+	ret0, _ := ret[0].(*v11.CSIDriverList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCSIDrivers indicates an expected call of GetCSIDrivers.
+func (mr *MockVolumeGetterMockRecorder) GetCSIDrivers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCSIDrivers", reflect.TypeOf((*MockVolumeGetter)(nil).GetCSIDrivers))
+}
+
+// GetPersistentVolumeClaimEvents mocks base method.
+func (m *MockVolumeGetter) GetPersistentVolumeClaimEvents() (*v10.EventList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersistentVolumeClaimEvents")
+	ret0, _ := ret[0].(*v10.EventList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersistentVolumeClaimEvents indicates an expected call of GetPersistentVolumeClaimEvents.
+func (mr *MockVolumeGetterMockRecorder) GetPersistentVolumeClaimEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersistentVolumeClaimEvents", reflect.TypeOf((*MockVolumeGetter)(nil).GetPersistentVolumeClaimEvents))
+}
+
+// GetPersistentVolumeClaims mocks base method.
+func (m *MockVolumeGetter) GetPersistentVolumeClaims() (*v10.PersistentVolumeClaimList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersistentVolumeClaims")
+	ret0, _ := ret[0].(*v10.PersistentVolumeClaimList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersistentVolumeClaims indicates an expected call of GetPersistentVolumeClaims.
+func (mr *MockVolumeGetterMockRecorder) GetPersistentVolumeClaims() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersistentVolumeClaims", reflect.TypeOf((*MockVolumeGetter)(nil).GetPersistentVolumeClaims))
+}
+
 // GetPersistentVolumes mocks base method.
-func (m *MockVolumeGetter) GetPersistentVolumes() (*v1.PersistentVolumeList, error) {
+func (m *MockVolumeGetter) GetPersistentVolumes() (*v10.PersistentVolumeList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPersistentVolumes")
-	ret0, _ := ret[0].(*v1.PersistentVolumeList)
+	ret0, _ := ret[0].(*v10.PersistentVolumeList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -64,3 +112,121 @@ func (mr *MockVolumeGetterMockRecorder) GetPersistentVolumes() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersistentVolumes", reflect.TypeOf((*MockVolumeGetter)(nil).GetPersistentVolumes))
 }
+
+// GetPods mocks base method.
+func (m *MockVolumeGetter) GetPods() (*v10.PodList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPods")
+	ret0, _ := ret[0].(*v10.PodList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPods indicates an expected call of GetPods.
+func (mr *MockVolumeGetterMockRecorder) GetPods() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPods", reflect.TypeOf((*MockVolumeGetter)(nil).GetPods))
+}
+
+// GetReplicaSets mocks base method.
+func (m *MockVolumeGetter) GetReplicaSets() (*v1.ReplicaSetList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplicaSets")
+	ret0, _ := ret[0].(*v1.ReplicaSetList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplicaSets indicates an expected call of GetReplicaSets.
+func (mr *MockVolumeGetterMockRecorder) GetReplicaSets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicaSets", reflect.TypeOf((*MockVolumeGetter)(nil).GetReplicaSets))
+}
+
+// GetStorageClasses mocks base method.
+func (m *MockVolumeGetter) GetStorageClasses() (*v11.StorageClassList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStorageClasses")
+	ret0, _ := ret[0].(*v11.StorageClassList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStorageClasses indicates an expected call of GetStorageClasses.
+func (mr *MockVolumeGetterMockRecorder) GetStorageClasses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorageClasses", reflect.TypeOf((*MockVolumeGetter)(nil).GetStorageClasses))
+}
+
+// GetVolumeAttachments mocks base method.
+func (m *MockVolumeGetter) GetVolumeAttachments() (*v11.VolumeAttachmentList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeAttachments")
+	ret0, _ := ret[0].(*v11.VolumeAttachmentList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeAttachments indicates an expected call of GetVolumeAttachments.
+func (mr *MockVolumeGetterMockRecorder) GetVolumeAttachments() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeAttachments", reflect.TypeOf((*MockVolumeGetter)(nil).GetVolumeAttachments))
+}
+
+// GetVolumeSnapshotContents mocks base method.
+func (m *MockVolumeGetter) GetVolumeSnapshotContents() (*unstructured.UnstructuredList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeSnapshotContents")
+	ret0, _ := ret[0].(*unstructured.UnstructuredList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeSnapshotContents indicates an expected call of GetVolumeSnapshotContents.
+func (mr *MockVolumeGetterMockRecorder) GetVolumeSnapshotContents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeSnapshotContents", reflect.TypeOf((*MockVolumeGetter)(nil).GetVolumeSnapshotContents))
+}
+
+// GetVolumeSnapshots mocks base method.
+func (m *MockVolumeGetter) GetVolumeSnapshots() (*unstructured.UnstructuredList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeSnapshots")
+	ret0, _ := ret[0].(*unstructured.UnstructuredList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeSnapshots indicates an expected call of GetVolumeSnapshots.
+func (mr *MockVolumeGetterMockRecorder) GetVolumeSnapshots() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeSnapshots", reflect.TypeOf((*MockVolumeGetter)(nil).GetVolumeSnapshots))
+}
+
+// HasSynced mocks base method.
+func (m *MockVolumeGetter) HasSynced() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSynced")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasSynced indicates an expected call of HasSynced.
+func (mr *MockVolumeGetterMockRecorder) HasSynced() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSynced", reflect.TypeOf((*MockVolumeGetter)(nil).HasSynced))
+}
+
+// SyncFailed mocks base method.
+func (m *MockVolumeGetter) SyncFailed() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncFailed")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SyncFailed indicates an expected call of SyncFailed.
+func (mr *MockVolumeGetterMockRecorder) SyncFailed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncFailed", reflect.TypeOf((*MockVolumeGetter)(nil).SyncFailed))
+}
@@ -0,0 +1,192 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tracer "github.com/dell/karavi-topology/internal/tracers"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Snapshot statuses
+const (
+	SnapshotStatusReady   = "Ready"
+	SnapshotStatusPending = "Pending"
+	SnapshotStatusError   = "Error"
+)
+
+// SnapshotInfo contains information about mapping a VolumeSnapshotContent to its VolumeSnapshot, its source volume
+// and the snapshot created on a storage system
+type SnapshotInfo struct {
+	Namespace                   string `json:"namespace"`
+	VolumeSnapshot              string `json:"volume_snapshot"`
+	VolumeSnapshotContent       string `json:"volume_snapshot_content"`
+	VolumeSnapshotClass         string `json:"volume_snapshot_class"`
+	Status                      string `json:"status"`
+	Driver                      string `json:"driver"`
+	RestoreSize                 string `json:"restore_size"`
+	SnapshotHandle              string `json:"snapshot_handle"`
+	SourcePersistentVolumeClaim string `json:"source_persistent_volume_claim"`
+	SourcePersistentVolume      string `json:"source_persistent_volume"`
+	StorageSystem               string `json:"storage_system"`
+	DeletionPolicy              string `json:"deletion_policy"`
+	CreatedTime                 string `json:"created_time"`
+	Error                       string `json:"error"`
+	Cluster                     string `json:"cluster"`
+}
+
+// GetVolumeSnapshots will return the snapshot contents created by a matching DriverName, mapped to their volume snapshots
+// and source volumes. It returns no snapshots when the snapshot custom resources are not installed.
+func (f VolumeFinder) GetVolumeSnapshots(ctx context.Context) ([]SnapshotInfo, error) {
+	ctx, span := tracer.GetTracer(ctx, "GetVolumeSnapshots")
+	defer span.End()
+
+	start := time.Now()
+	defer f.timeSince(start, "GetVolumeSnapshots")
+
+	snapshotInfo := make([]SnapshotInfo, 0)
+
+	contents, err := f.API.GetVolumeSnapshotContents()
+	if errors.Is(err, ErrResourceNotInstalled) {
+		f.Logger.Debug("volume snapshot custom resources are not installed")
+		return snapshotInfo, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := f.API.GetVolumeSnapshots()
+	if err != nil && !errors.Is(err, ErrResourceNotInstalled) {
+		return nil, err
+	}
+
+	snapshotsByName := make(map[string]*unstructured.Unstructured)
+	if snapshots != nil {
+		for i := range snapshots.Items {
+			snapshot := &snapshots.Items[i]
+			snapshotsByName[snapshot.GetNamespace()+"/"+snapshot.GetName()] = snapshot
+		}
+	}
+
+	settings := f.Settings()
+	drivers, err := f.drivers(settings)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := f.snapshotSources(drivers, settings.extractors())
+	if err != nil {
+		return nil, err
+	}
+	for _, content := range contents.Items {
+		driver, _, _ := unstructured.NestedString(content.Object, "spec", "driver")
+		if !drivers(driver) {
+			continue
+		}
+
+		info := SnapshotInfo{
+			VolumeSnapshotContent: content.GetName(),
+			Driver:                driver,
+			CreatedTime:           content.GetCreationTimestamp().String(),
+			Cluster:               f.Cluster,
+		}
+		info.Namespace, _, _ = unstructured.NestedString(content.Object, "spec", "volumeSnapshotRef", "namespace")
+		info.VolumeSnapshot, _, _ = unstructured.NestedString(content.Object, "spec", "volumeSnapshotRef", "name")
+		info.VolumeSnapshotClass, _, _ = unstructured.NestedString(content.Object, "spec", "volumeSnapshotClassName")
+		info.DeletionPolicy, _, _ = unstructured.NestedString(content.Object, "spec", "deletionPolicy")
+		info.SnapshotHandle, _, _ = unstructured.NestedString(content.Object, "status", "snapshotHandle")
+		if info.SnapshotHandle == "" {
+			// pre-provisioned contents name the storage system snapshot in their spec
+			info.SnapshotHandle, _, _ = unstructured.NestedString(content.Object, "spec", "source", "snapshotHandle")
+		}
+		if size, ok, _ := unstructured.NestedInt64(content.Object, "status", "restoreSize"); ok {
+			info.RestoreSize = resource.NewQuantity(size, resource.BinarySI).String()
+		}
+		if created, ok, _ := unstructured.NestedInt64(content.Object, "status", "creationTime"); ok {
+			// the time the storage system took the snapshot, in nanoseconds
+			info.CreatedTime = time.Unix(0, created).String()
+		}
+		info.Status, info.Error = snapshotStatus(&content)
+
+		if volumeHandle, _, _ := unstructured.NestedString(content.Object, "spec", "source", "volumeHandle"); volumeHandle != "" {
+			if source, ok := sources[volumeHandle]; ok {
+				info.SourcePersistentVolume = source.persistentVolume
+				info.SourcePersistentVolumeClaim = source.persistentVolumeClaim
+				info.StorageSystem = source.storageSystem
+			}
+		}
+
+		if snapshot, ok := snapshotsByName[info.Namespace+"/"+info.VolumeSnapshot]; ok {
+			if claim, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName"); claim != "" {
+				info.SourcePersistentVolumeClaim = claim
+			}
+			// the snapshot reports errors of the snapshot controller that the content does not
+			if status, message := snapshotStatus(snapshot); info.Error == "" && status == SnapshotStatusError {
+				info.Status, info.Error = status, message
+			}
+		}
+
+		snapshotInfo = append(snapshotInfo, info)
+	}
+	return snapshotInfo, nil
+}
+
+// snapshotSource is the volume a snapshot was taken from
+type snapshotSource struct {
+	persistentVolume      string
+	persistentVolumeClaim string
+	storageSystem         string
+}
+
+// snapshotSources returns the volumes of the drivers indexed by volume handle, with the storage system given by the
+// extractors; only the volume cache is read, since the pods and attachments of the volumes are not needed
+func (f VolumeFinder) snapshotSources(drivers func(string) bool, extractors *ExtractorRegistry) (map[string]snapshotSource, error) {
+	volumes, err := f.API.GetPersistentVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]snapshotSource)
+	for i := range volumes.Items {
+		volume := &volumes.Items[i]
+		if volume.Spec.CSI == nil || !drivers(volume.Spec.CSI.Driver) {
+			continue
+		}
+		source := snapshotSource{
+			persistentVolume: volume.Name,
+			storageSystem:    extractors.Lookup(volume.Spec.CSI.Driver).Extract(volume).StorageSystem,
+		}
+		if claim := volume.Spec.ClaimRef; claim != nil {
+			source.persistentVolumeClaim = claim.Name
+		}
+		sources[volume.Spec.CSI.VolumeHandle] = source
+	}
+	return sources, nil
+}
+
+// snapshotStatus returns the status and error message of a volume snapshot or volume snapshot content
+func snapshotStatus(object *unstructured.Unstructured) (string, string) {
+	if message, _, _ := unstructured.NestedString(object.Object, "status", "error", "message"); message != "" {
+		return SnapshotStatusError, message
+	}
+	if ready, _, _ := unstructured.NestedBool(object.Object, "status", "readyToUse"); ready {
+		return SnapshotStatusReady, ""
+	}
+	return SnapshotStatusPending, ""
+}
@@ -0,0 +1,183 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/k8s/mocks"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func snapshotContent(name, driver string, spec, status map[string]interface{}) unstructured.Unstructured {
+	spec["driver"] = driver
+	object := map[string]interface{}{
+		"apiVersion": "snapshot.storage.k8s.io/v1",
+		"kind":       "VolumeSnapshotContent",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec":       spec,
+	}
+	if status != nil {
+		object["status"] = status
+	}
+	return unstructured.Unstructured{Object: object}
+}
+
+func Test_VolumeFinderGetVolumeSnapshots(t *testing.T) {
+	created := time.Date(2020, 7, 28, 20, 0, 0, 0, time.UTC)
+
+	volumes := &corev1.PersistentVolumeList{
+		Items: []corev1.PersistentVolume{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+				Spec: corev1.PersistentVolumeSpec{
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{
+							Driver:           "csi-vxflexos.dellemc.com",
+							VolumeHandle:     "7045c4cc20dffc0f-5ef1c3e000000007",
+							VolumeAttributes: map[string]string{"StorageSystem": "7045c4cc20dffc0f"},
+						},
+					},
+					ClaimRef: &corev1.ObjectReference{Name: "pvc-1", Namespace: "ns-1"},
+				},
+			},
+		},
+	}
+
+	contents := &unstructured.UnstructuredList{
+		Items: []unstructured.Unstructured{
+			snapshotContent("snapcontent-1", "csi-vxflexos.dellemc.com", map[string]interface{}{
+				"deletionPolicy":          "Delete",
+				"volumeSnapshotClassName": "vxflexos-snapclass",
+				"source":                  map[string]interface{}{"volumeHandle": "7045c4cc20dffc0f-5ef1c3e000000007"},
+				"volumeSnapshotRef":       map[string]interface{}{"name": "snap-1", "namespace": "ns-1"},
+			}, map[string]interface{}{
+				"readyToUse":     true,
+				"restoreSize":    int64(8589934592),
+				"snapshotHandle": "7045c4cc20dffc0f-5ef1c3e100000008",
+				"creationTime":   created.UnixNano(),
+			}),
+			snapshotContent("snapcontent-2", "csi-vxflexos.dellemc.com", map[string]interface{}{
+				"deletionPolicy":    "Retain",
+				"source":            map[string]interface{}{"volumeHandle": "7045c4cc20dffc0f-5ef1c3e000000007"},
+				"volumeSnapshotRef": map[string]interface{}{"name": "snap-2", "namespace": "ns-1"},
+			}, nil),
+			snapshotContent("snapcontent-3", "csi-powerstore.dellemc.com", map[string]interface{}{
+				"source":            map[string]interface{}{"snapshotHandle": "other"},
+				"volumeSnapshotRef": map[string]interface{}{"name": "snap-3", "namespace": "ns-2"},
+			}, nil),
+		},
+	}
+	snapshots := &unstructured.UnstructuredList{
+		Items: []unstructured.Unstructured{
+			{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "snap-2", "namespace": "ns-1"},
+				"spec":     map[string]interface{}{"source": map[string]interface{}{"persistentVolumeClaimName": "pvc-1"}},
+				"status":   map[string]interface{}{"error": map[string]interface{}{"message": "snapshot quota exceeded"}},
+			}},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	api := mocks.NewMockVolumeGetter(ctrl)
+	api.EXPECT().GetVolumeSnapshotContents().Times(1).Return(contents, nil)
+	api.EXPECT().GetVolumeSnapshots().Times(1).Return(snapshots, nil)
+	// the sources are read from the volume cache only, not from the pods, attachments or claims
+	api.EXPECT().GetPersistentVolumes().Times(1).Return(volumes, nil)
+
+	finder := k8s.VolumeFinder{
+		API:         api,
+		DriverNames: []string{"csi-vxflexos.dellemc.com"},
+		Logger:      logrus.New(),
+	}
+	result, err := finder.GetVolumeSnapshots(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []k8s.SnapshotInfo{
+		{
+			Namespace:                   "ns-1",
+			VolumeSnapshot:              "snap-1",
+			VolumeSnapshotContent:       "snapcontent-1",
+			VolumeSnapshotClass:         "vxflexos-snapclass",
+			Status:                      "Ready",
+			Driver:                      "csi-vxflexos.dellemc.com",
+			RestoreSize:                 "8Gi",
+			SnapshotHandle:              "7045c4cc20dffc0f-5ef1c3e100000008",
+			SourcePersistentVolumeClaim: "pvc-1",
+			SourcePersistentVolume:      "pv-1",
+			StorageSystem:               "7045c4cc20dffc0f",
+			DeletionPolicy:              "Delete",
+			CreatedTime:                 time.Unix(0, created.UnixNano()).String(),
+		},
+		{
+			Namespace:                   "ns-1",
+			VolumeSnapshot:              "snap-2",
+			VolumeSnapshotContent:       "snapcontent-2",
+			Status:                      "Error",
+			Driver:                      "csi-vxflexos.dellemc.com",
+			SourcePersistentVolumeClaim: "pvc-1",
+			SourcePersistentVolume:      "pv-1",
+			StorageSystem:               "7045c4cc20dffc0f",
+			DeletionPolicy:              "Retain",
+			CreatedTime:                 metav1.Time{}.String(),
+			Error:                       "snapshot quota exceeded",
+		},
+	}, result)
+}
+
+func Test_VolumeFinderGetVolumeSnapshotsErrors(t *testing.T) {
+	tests := map[string]struct {
+		contentsErr    error
+		expectedError  bool
+		expectedLength int
+	}{
+		"snapshot resources not installed": {
+			contentsErr:    k8s.ErrResourceNotInstalled,
+			expectedLength: 0,
+		},
+		"error calling k8s": {
+			contentsErr:   errors.New("error"),
+			expectedError: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			api := mocks.NewMockVolumeGetter(ctrl)
+			api.EXPECT().GetVolumeSnapshotContents().Times(1).Return(nil, tc.contentsErr)
+
+			finder := k8s.VolumeFinder{API: api, Logger: logrus.New()}
+			result, err := finder.GetVolumeSnapshots(context.TODO())
+			assert.Equal(t, tc.expectedError, err != nil)
+			if !tc.expectedError {
+				assert.Equal(t, tc.expectedLength, len(result))
+			}
+		})
+	}
+}
@@ -18,12 +18,18 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	tracer "github.com/dell/karavi-topology/internal/tracers"
 	"github.com/sirupsen/logrus"
@@ -36,6 +42,10 @@ const (
 	CsiDriverNamePowerScale = "isilon"
 	// CsiDriverNamePowerMax CSI PowerMax Name
 	CsiDriverNamePowerMax = "powermax"
+	// PersistentVolumeStatusUnbound is the status of a pending persistent volume claim that has no volume yet
+	PersistentVolumeStatusUnbound = "Unbound"
+	// defaultStorageClassAnnotation marks the storage class of claims that do not name one
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
 )
 
 // VolumeGetter is an interface for getting a list of persistent volume information
@@ -43,30 +53,83 @@ const (
 //go:generate mockgen -destination=mocks/volume_getter_mocks.go -package=mocks github.com/dell/karavi-topology/internal/k8s VolumeGetter
 type VolumeGetter interface {
 	GetPersistentVolumes() (*corev1.PersistentVolumeList, error)
+	GetPods() (*corev1.PodList, error)
+	GetReplicaSets() (*appsv1.ReplicaSetList, error)
+	GetVolumeAttachments() (*storagev1.VolumeAttachmentList, error)
+	GetPersistentVolumeClaims() (*corev1.PersistentVolumeClaimList, error)
+	GetStorageClasses() (*storagev1.StorageClassList, error)
+	GetCSIDrivers() (*storagev1.CSIDriverList, error)
+	GetPersistentVolumeClaimEvents() (*corev1.EventList, error)
+	GetVolumeSnapshots() (*unstructured.UnstructuredList, error)
+	GetVolumeSnapshotContents() (*unstructured.UnstructuredList, error)
+	HasSynced() bool
+	SyncFailed() bool
 }
 
 // VolumeFinder is a volume finder that will query the Kubernetes API for Persistent Volumes created by a matching DriverName
 type VolumeFinder struct {
 	API         VolumeGetter
 	DriverNames []string
-	Logger      *logrus.Logger
+	// DriverMatcher selects the drivers by pattern; it takes precedence over DriverNames
+	DriverMatcher *DriverMatcher
+	// Discovery selects the installed CSI drivers to use when there are no driver names or patterns
+	Discovery *DriverMatcher
+	Logger    *logrus.Logger
+	// Extractors maps the volumes of each driver to their storage system; the built-in Dell drivers are used when nil
+	Extractors *ExtractorRegistry
+	// Cluster is the name of the cluster the volumes are found in, set on every volume and snapshot
+	Cluster string
+	// settings holds the driver settings published by SetSettings, which replace DriverNames, DriverMatcher, Discovery
+	// and Extractors; nil until the first call
+	settings *atomic.Pointer[DriverSettings]
+}
+
+// DriverSettings are the settings of a volume finder that change with the configuration: the drivers whose volumes are
+// found and the extractors mapping their volumes to storage systems
+type DriverSettings struct {
+	DriverNames   []string
+	DriverMatcher *DriverMatcher
+	Discovery     *DriverMatcher
+	Extractors    *ExtractorRegistry
 }
 
 // VolumeInfo contains information about mapping a Persistent Volume to the volume created on a storage system
 type VolumeInfo struct {
-	Namespace               string `json:"namespace"`
-	PersistentVolumeClaim   string `json:"persistent_volume_claim"`
-	PersistentVolumeStatus  string `json:"volume_status"`
-	VolumeClaimName         string `json:"volume_claim_name"`
-	PersistentVolume        string `json:"persistent_volume"`
-	StorageClass            string `json:"storage_class"`
-	Driver                  string `json:"driver"`
-	ProvisionedSize         string `json:"provisioned_size"`
-	StorageSystemVolumeName string `json:"storage_system_volume_name"`
-	StoragePoolName         string `json:"storage_pool_name"`
-	StorageSystem           string `json:"storage_system"`
-	Protocol                string `json:"protocol"`
-	CreatedTime             string `json:"created_time"`
+	Namespace               string    `json:"namespace"`
+	PersistentVolumeClaim   string    `json:"persistent_volume_claim"`
+	PersistentVolumeStatus  string    `json:"volume_status"`
+	VolumeClaimName         string    `json:"volume_claim_name"`
+	PersistentVolume        string    `json:"persistent_volume"`
+	StorageClass            string    `json:"storage_class"`
+	Driver                  string    `json:"driver"`
+	ProvisionedSize         string    `json:"provisioned_size"`
+	StorageSystemVolumeName string    `json:"storage_system_volume_name"`
+	StoragePoolName         string    `json:"storage_pool_name"`
+	StorageSystem           string    `json:"storage_system"`
+	Protocol                string    `json:"protocol"`
+	CreatedTime             string    `json:"created_time"`
+	Pods                    []PodInfo `json:"pods"`
+	AttachedNode            string    `json:"attached_node"`
+	AttachStatus            string    `json:"attach_status"`
+	AttachError             string    `json:"attach_error"`
+	// Message is the last event reported for the claim of an unbound volume, typically the provisioning error
+	Message string `json:"message"`
+	Cluster string `json:"cluster"`
+}
+
+// Attach statuses of a volume attachment
+const (
+	AttachStatusAttaching = "Attaching"
+	AttachStatusAttached  = "Attached"
+	AttachStatusDetaching = "Detaching"
+)
+
+// PodInfo describes a pod mounting the persistent volume claim of a volume and the controller that owns it
+type PodInfo struct {
+	Name      string `json:"name"`
+	Node      string `json:"node"`
+	OwnerKind string `json:"owner_kind"`
+	OwnerName string `json:"owner_name"`
 }
 
 // GetPersistentVolumes will return a list of persistent volume information
@@ -83,15 +146,29 @@ func (f VolumeFinder) GetPersistentVolumes(ctx context.Context) ([]VolumeInfo, e
 	if err != nil {
 		return nil, err
 	}
+	settings := f.Settings()
+	drivers, err := f.drivers(settings)
+	if err != nil {
+		return nil, err
+	}
+	extractors := settings.extractors()
+	pods, err := f.podsByClaim()
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := f.attachmentsByVolume()
+	if err != nil {
+		return nil, err
+	}
 
 	for _, volume := range volumes.Items {
-		if volume.Spec.CSI != nil && Contains(f.DriverNames, volume.Spec.CSI.Driver) {
+		if volume.Spec.CSI != nil && drivers(volume.Spec.CSI.Driver) {
 			capacity := volume.Spec.Capacity[v1.ResourceStorage]
 			claim := volume.Spec.ClaimRef
 			status := volume.Status
-			volumeHandle := volume.Spec.CSI.VolumeHandle
 
 			f.Logger.WithField("volume_attributes", volume.Spec.CSI.VolumeAttributes).Debug("volumefinder volumes attributes map")
+			attributes := extractors.Lookup(volume.Spec.CSI.Driver).Extract(&volume)
 			info := VolumeInfo{
 				Namespace:               claim.Namespace,
 				PersistentVolumeClaim:   string(claim.UID),
@@ -101,57 +178,281 @@ func (f VolumeFinder) GetPersistentVolumes(ctx context.Context) ([]VolumeInfo, e
 				StorageClass:            volume.Spec.StorageClassName,
 				Driver:                  volume.Spec.CSI.Driver,
 				ProvisionedSize:         capacity.String(),
-				StorageSystemVolumeName: volume.Spec.CSI.VolumeAttributes["Name"],
-				StoragePoolName:         volume.Spec.CSI.VolumeAttributes["StoragePoolName"],
-				StorageSystem:           volume.Spec.CSI.VolumeAttributes["StorageSystem"],
-				Protocol:                volume.Spec.CSI.VolumeAttributes["Protocol"],
+				StorageSystemVolumeName: attributes.StorageSystemVolumeName,
+				StoragePoolName:         attributes.StoragePoolName,
+				StorageSystem:           attributes.StorageSystem,
+				Protocol:                attributes.Protocol,
 				CreatedTime:             volume.CreationTimestamp.String(),
+				Pods:                    pods[claim.Namespace+"/"+claim.Name],
+				Cluster:                 f.Cluster,
 			}
+			info.AttachedNode, info.AttachStatus, info.AttachError = attachmentInfo(attachments[volume.Name])
+
 			// powerstore do not return this value, csi created volume has storage volume name and pv name same
-			if info.StorageSystemVolumeName == "" || len(info.StorageSystemVolumeName) == 0 {
+			if info.StorageSystemVolumeName == "" {
 				info.StorageSystemVolumeName = volume.Name
 			}
 
-			// powerflex will provide storagesystem id and powerstore will provide array IP
-			if info.StorageSystem == "" || len(info.StorageSystem) == 0 {
-				info.StorageSystem = volume.Spec.CSI.VolumeAttributes["arrayID"]
+			// powerstore volume do not have storage pool unlike powerflex
+			if info.StoragePoolName == "" {
+				info.StoragePoolName = "N/A"
 			}
 
-			// set StoregeSystem and Protocol for PowerScale
-			if strings.Contains(info.Driver, CsiDriverNamePowerScale) {
-				info.StorageSystem = volume.Spec.CSI.VolumeAttributes["ClusterName"] + ":" + volume.Spec.CSI.VolumeAttributes["AccessZone"]
-				info.Protocol = ProtocolNfs
-			}
+			volumeInfo = append(volumeInfo, info)
+		}
+	}
+	unbound, err := f.unboundClaims(drivers, pods)
+	if err != nil {
+		return nil, err
+	}
+	return append(volumeInfo, unbound...), nil
+}
 
-			// set attributes for PowerMax
-			if strings.Contains(info.Driver, CsiDriverNamePowerMax) {
-				info.StorageSystemVolumeName = parsePowerMaxVolumeName(volumeHandle)
-				info.StorageSystem = volume.Spec.CSI.VolumeAttributes["powermax/SYMID"]
-				info.StoragePoolName = volume.Spec.CSI.VolumeAttributes["SRP"]
-				if info.Protocol == "" {
-					info.Protocol = "N/A"
+// Settings returns the driver settings in use: the settings last published by SetSettings, or else the fields of the
+// finder
+func (f VolumeFinder) Settings() DriverSettings {
+	if f.settings != nil {
+		if settings := f.settings.Load(); settings != nil {
+			return *settings
+		}
+	}
+	return DriverSettings{
+		DriverNames:   f.DriverNames,
+		DriverMatcher: f.DriverMatcher,
+		Discovery:     f.Discovery,
+		Extractors:    f.Extractors,
+	}
+}
+
+// SetSettings publishes new driver settings. They replace the previous settings atomically, so the configuration can
+// change while the finder serves requests; the first call must happen before the finder is shared between goroutines.
+func (f *VolumeFinder) SetSettings(settings DriverSettings) {
+	if f.settings == nil {
+		f.settings = new(atomic.Pointer[DriverSettings])
+	}
+	f.settings.Store(&settings)
+}
+
+// extractors returns the attribute extractor registry of the settings, or the built-in extractors when there is none
+func (s DriverSettings) extractors() *ExtractorRegistry {
+	if s.Extractors != nil {
+		return s.Extractors
+	}
+	return defaultExtractors
+}
+
+// Ready returns true once the persistent volume cache has completed its initial sync
+func (f VolumeFinder) Ready() bool {
+	return f.API.HasSynced()
+}
+
+// SyncFailed returns true while the persistent volume cache has not synced because the cluster could not be reached or
+// listed
+func (f VolumeFinder) SyncFailed() bool {
+	return f.API.SyncFailed()
+}
+
+// unboundClaims returns the pending persistent volume claims whose storage class is provisioned by one of the
+// drivers, with the status PersistentVolumeStatusUnbound. Unbound claims are optional topology, so when the claims or
+// storage classes cannot be listed this is logged and no claims are returned.
+func (f VolumeFinder) unboundClaims(drivers func(string) bool, pods map[string][]PodInfo) ([]VolumeInfo, error) {
+	claims, err := f.API.GetPersistentVolumeClaims()
+	if err != nil {
+		return nil, f.optional(err, "getting persistent volume claims; unbound claims are not returned")
+	}
+	classes, err := f.API.GetStorageClasses()
+	if err != nil {
+		return nil, f.optional(err, "getting storage classes; unbound claims are not returned")
+	}
+
+	provisioners := make(map[string]string, len(classes.Items))
+	defaultClass := ""
+	for _, class := range classes.Items {
+		provisioners[class.Name] = class.Provisioner
+		if class.Annotations[defaultStorageClassAnnotation] == "true" {
+			defaultClass = class.Name
+		}
+	}
+
+	unbound := make([]VolumeInfo, 0)
+	for _, claim := range claims.Items {
+		if claim.Status.Phase != corev1.ClaimPending {
+			continue
+		}
+		class := defaultClass
+		if claim.Spec.StorageClassName != nil {
+			class = *claim.Spec.StorageClassName
+		}
+		provisioner := provisioners[class]
+		if provisioner == "" || !drivers(provisioner) {
+			continue
+		}
+
+		request := claim.Spec.Resources.Requests[v1.ResourceStorage]
+		unbound = append(unbound, VolumeInfo{
+			Namespace:              claim.Namespace,
+			PersistentVolumeClaim:  string(claim.UID),
+			VolumeClaimName:        claim.Name,
+			PersistentVolumeStatus: PersistentVolumeStatusUnbound,
+			StorageClass:           class,
+			Driver:                 provisioner,
+			ProvisionedSize:        request.String(),
+			CreatedTime:            claim.CreationTimestamp.String(),
+			Pods:                   pods[claim.Namespace+"/"+claim.Name],
+			Cluster:                f.Cluster,
+		})
+	}
+	if len(unbound) == 0 {
+		return unbound, nil
+	}
+
+	messages, err := f.lastClaimEvents()
+	if err != nil {
+		return nil, err
+	}
+	for i := range unbound {
+		unbound[i].Message = messages[unbound[i].PersistentVolumeClaim]
+	}
+	return unbound, nil
+}
+
+// lastClaimEvents returns the message of the last event of each persistent volume claim, indexed by claim UID
+func (f VolumeFinder) lastClaimEvents() (map[string]string, error) {
+	events, err := f.API.GetPersistentVolumeClaimEvents()
+	if err != nil {
+		return nil, f.optional(err, "getting persistent volume claim events; unbound claims are returned without messages")
+	}
+
+	last := make(map[string]time.Time)
+	messages := make(map[string]string)
+	for _, event := range events.Items {
+		uid := string(event.InvolvedObject.UID)
+		at := eventTime(event)
+		if previous, ok := last[uid]; !ok || !at.Before(previous) {
+			last[uid] = at
+			messages[uid] = event.Message
+		}
+	}
+	return messages, nil
+}
+
+// eventTime returns the last time an event was reported
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// podsByClaim returns the running pods indexed by the namespace/name of the persistent volume claims they mount.
+// Pods are optional topology, so when they cannot be listed this is logged and the volumes are returned without pods.
+func (f VolumeFinder) podsByClaim() (map[string][]PodInfo, error) {
+	pods, err := f.API.GetPods()
+	if err != nil {
+		return nil, f.optional(err, "getting pods; volumes are returned without pods")
+	}
+	replicaSets, err := f.API.GetReplicaSets()
+	if err != nil {
+		if err := f.optional(err, "getting replica sets; pods owned by replica sets are not resolved to deployments"); err != nil {
+			return nil, err
+		}
+		replicaSets = &appsv1.ReplicaSetList{}
+	}
+
+	replicaSetOwners := make(map[string]*metav1.OwnerReference, len(replicaSets.Items))
+	for i := range replicaSets.Items {
+		replicaSet := &replicaSets.Items[i]
+		replicaSetOwners[replicaSet.Namespace+"/"+replicaSet.Name] = metav1.GetControllerOf(replicaSet)
+	}
+
+	claims := make(map[string][]PodInfo)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		info := PodInfo{Name: pod.Name, Node: pod.Spec.NodeName}
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			info.OwnerKind, info.OwnerName = owner.Kind, owner.Name
+			// pods of a deployment are owned by one of its replica sets
+			if owner.Kind == "ReplicaSet" {
+				if deployment := replicaSetOwners[pod.Namespace+"/"+owner.Name]; deployment != nil {
+					info.OwnerKind, info.OwnerName = deployment.Kind, deployment.Name
 				}
 			}
+		}
 
-			// powerstore volume do not have storage pool unlike powerflex
-			if info.StoragePoolName == "" || len(info.StoragePoolName) == 0 {
-				info.StoragePoolName = "N/A"
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				key := pod.Namespace + "/" + volume.PersistentVolumeClaim.ClaimName
+				claims[key] = append(claims[key], info)
 			}
+		}
+	}
+	return claims, nil
+}
 
-			volumeInfo = append(volumeInfo, info)
+// attachmentsByVolume returns the volume attachments indexed by persistent volume name. Attachments are optional
+// topology, so when they cannot be listed this is logged and the volumes are returned without attachments.
+func (f VolumeFinder) attachmentsByVolume() (map[string][]storagev1.VolumeAttachment, error) {
+	attachments, err := f.API.GetVolumeAttachments()
+	if err != nil {
+		return nil, f.optional(err, "getting volume attachments; volumes are returned without attachments")
+	}
+
+	volumes := make(map[string][]storagev1.VolumeAttachment)
+	for _, attachment := range attachments.Items {
+		if name := attachment.Spec.Source.PersistentVolumeName; name != nil {
+			volumes[*name] = append(volumes[*name], attachment)
 		}
 	}
-	return volumeInfo, nil
+	return volumes, nil
 }
 
-// parsePowerMaxVolumeName parse PowerMax PV volumeHandle and return storage volume name
-func parsePowerMaxVolumeName(volumeHandle string) string {
-	ele := strings.Split(volumeHandle, "-")
-	if len(ele) == 7 {
-		return fmt.Sprintf("%s:%s", ele[6], strings.Join(ele[0:5], "-"))
+// optional returns nil and logs message when err means that a resource of optional topology cannot be listed, and err
+// otherwise, e.g. while the cache of the resource has not synced
+func (f VolumeFinder) optional(err error, message string) error {
+	if errors.Is(err, ErrListDenied) {
+		f.Logger.WithError(err).Warn(message)
+		return nil
 	}
+	return err
+}
+
+// attachmentInfo returns the nodes, statuses and errors of the attachments of a volume. Volumes attached to several
+// nodes list the nodes and their statuses comma separated in the same order, and their errors prefixed with the node.
+func attachmentInfo(attachments []storagev1.VolumeAttachment) (string, string, string) {
+	nodes := make([]string, 0, len(attachments))
+	statuses := make([]string, 0, len(attachments))
+	errs := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		nodes = append(nodes, attachment.Spec.NodeName)
 
-	return volumeHandle
+		status := AttachStatusAttaching
+		if attachment.DeletionTimestamp != nil || attachment.Status.DetachError != nil {
+			status = AttachStatusDetaching
+		} else if attachment.Status.Attached {
+			status = AttachStatusAttached
+		}
+		statuses = append(statuses, status)
+
+		for _, attachErr := range []*storagev1.VolumeError{attachment.Status.AttachError, attachment.Status.DetachError} {
+			if attachErr == nil || attachErr.Message == "" {
+				continue
+			}
+			if len(attachments) > 1 {
+				errs = append(errs, fmt.Sprintf("%s: %s", attachment.Spec.NodeName, attachErr.Message))
+			} else {
+				errs = append(errs, attachErr.Message)
+			}
+		}
+	}
+	return strings.Join(nodes, ", "), strings.Join(statuses, ", "), strings.Join(errs, "; ")
 }
 
 // Contains will return true if the slice contains the given value
@@ -19,6 +19,7 @@ package k8s_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -26,10 +27,13 @@ import (
 	"github.com/dell/karavi-topology/internal/k8s/mocks"
 	"github.com/sirupsen/logrus"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -129,6 +133,11 @@ func Test_K8sPersistentVolumeFinder(t *testing.T) {
 			}
 
 			api.EXPECT().GetPersistentVolumes().Times(1).Return(volumes, nil)
+			api.EXPECT().GetPods().Times(1).Return(&corev1.PodList{}, nil)
+			api.EXPECT().GetReplicaSets().Times(1).Return(&appsv1.ReplicaSetList{}, nil)
+			api.EXPECT().GetVolumeAttachments().Times(1).Return(&storagev1.VolumeAttachmentList{}, nil)
+			api.EXPECT().GetPersistentVolumeClaims().Times(1).Return(&corev1.PersistentVolumeClaimList{}, nil)
+			api.EXPECT().GetStorageClasses().Times(1).Return(&storagev1.StorageClassList{}, nil)
 
 			finder := k8s.VolumeFinder{
 				API:         api,
@@ -314,6 +323,11 @@ func Test_K8sPersistentVolumeFinder(t *testing.T) {
 			}
 
 			api.EXPECT().GetPersistentVolumes().Times(1).Return(volumes, nil)
+			api.EXPECT().GetPods().Times(1).Return(&corev1.PodList{}, nil)
+			api.EXPECT().GetReplicaSets().Times(1).Return(&appsv1.ReplicaSetList{}, nil)
+			api.EXPECT().GetVolumeAttachments().Times(1).Return(&storagev1.VolumeAttachmentList{}, nil)
+			api.EXPECT().GetPersistentVolumeClaims().Times(1).Return(&corev1.PersistentVolumeClaimList{}, nil)
+			api.EXPECT().GetStorageClasses().Times(1).Return(&storagev1.StorageClassList{}, nil)
 
 			finder := k8s.VolumeFinder{
 				API:         api,
@@ -381,6 +395,360 @@ func Test_K8sPersistentVolumeFinder(t *testing.T) {
 				},
 			})), ctrl
 		},
+		"success resolving the pods mounting each volume": func(*testing.T) (k8s.VolumeFinder, []checkFn, *gomock.Controller) {
+			ctrl := gomock.NewController(t)
+			api := mocks.NewMockVolumeGetter(ctrl)
+
+			t1, err := time.Parse(time.RFC3339, "2020-07-28T20:00:00+00:00")
+			assert.Nil(t, err)
+
+			volumes := &corev1.PersistentVolumeList{
+				Items: []corev1.PersistentVolume{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              "persistent-volume-name",
+							CreationTimestamp: metav1.Time{Time: t1},
+						},
+						Spec: corev1.PersistentVolumeSpec{
+							Capacity: map[corev1.ResourceName]resource.Quantity{
+								v1.ResourceStorage: resource.MustParse("16Gi"),
+							},
+							PersistentVolumeSource: corev1.PersistentVolumeSource{
+								CSI: &corev1.CSIPersistentVolumeSource{
+									Driver: "csi-vxflexos.dellemc.com",
+									VolumeAttributes: map[string]string{
+										"Name":            "storage-system-volume-name",
+										"StoragePoolName": "storage-pool-name",
+									},
+								},
+							},
+							ClaimRef: &corev1.ObjectReference{
+								Name:      "pvc-name",
+								Namespace: "namespace-1",
+								UID:       "pvc-uid",
+							},
+							StorageClassName: "storage-class-name",
+						},
+						Status: corev1.PersistentVolumeStatus{
+							Phase: "Bound",
+						},
+					},
+				},
+			}
+
+			controller := true
+			claimVolume := func(claim string) []corev1.Volume {
+				return []corev1.Volume{{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+					},
+				}}
+			}
+			pods := &corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "web-7d4b9c-x2x4z",
+							Namespace: "namespace-1",
+							OwnerReferences: []metav1.OwnerReference{
+								{Kind: "ReplicaSet", Name: "web-7d4b9c", Controller: &controller},
+							},
+						},
+						Spec:   corev1.PodSpec{NodeName: "node-1", Volumes: claimVolume("pvc-name")},
+						Status: corev1.PodStatus{Phase: corev1.PodRunning},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "db-0",
+							Namespace: "namespace-1",
+							OwnerReferences: []metav1.OwnerReference{
+								{Kind: "StatefulSet", Name: "db", Controller: &controller},
+							},
+						},
+						Spec:   corev1.PodSpec{NodeName: "node-2", Volumes: claimVolume("pvc-name")},
+						Status: corev1.PodStatus{Phase: corev1.PodRunning},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "namespace-1"},
+						Spec:       corev1.PodSpec{NodeName: "node-3", Volumes: claimVolume("pvc-name")},
+						Status:     corev1.PodStatus{Phase: corev1.PodPending},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "completed", Namespace: "namespace-1"},
+						Spec:       corev1.PodSpec{NodeName: "node-1", Volumes: claimVolume("pvc-name")},
+						Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "namespace-2"},
+						Spec:       corev1.PodSpec{NodeName: "node-1", Volumes: claimVolume("pvc-name")},
+						Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+					},
+				},
+			}
+			replicaSets := &appsv1.ReplicaSetList{
+				Items: []appsv1.ReplicaSet{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "web-7d4b9c",
+							Namespace: "namespace-1",
+							OwnerReferences: []metav1.OwnerReference{
+								{Kind: "Deployment", Name: "web", Controller: &controller},
+							},
+						},
+					},
+				},
+			}
+
+			api.EXPECT().GetPersistentVolumes().Times(1).Return(volumes, nil)
+			api.EXPECT().GetPods().Times(1).Return(pods, nil)
+			api.EXPECT().GetReplicaSets().Times(1).Return(replicaSets, nil)
+			api.EXPECT().GetVolumeAttachments().Times(1).Return(&storagev1.VolumeAttachmentList{}, nil)
+			api.EXPECT().GetPersistentVolumeClaims().Times(1).Return(&corev1.PersistentVolumeClaimList{}, nil)
+			api.EXPECT().GetStorageClasses().Times(1).Return(&storagev1.StorageClassList{}, nil)
+
+			finder := k8s.VolumeFinder{
+				API:         api,
+				DriverNames: []string{"csi-vxflexos.dellemc.com"},
+				Logger:      logrus.New(),
+			}
+			return finder, check(hasNoError, checkExpectedOutput([]k8s.VolumeInfo{
+				{
+					Namespace:               "namespace-1",
+					PersistentVolumeClaim:   "pvc-uid",
+					PersistentVolumeStatus:  "Bound",
+					VolumeClaimName:         "pvc-name",
+					PersistentVolume:        "persistent-volume-name",
+					StorageClass:            "storage-class-name",
+					Driver:                  "csi-vxflexos.dellemc.com",
+					ProvisionedSize:         "16Gi",
+					StorageSystemVolumeName: "storage-system-volume-name",
+					StoragePoolName:         "storage-pool-name",
+					CreatedTime:             t1.String(),
+					Pods: []k8s.PodInfo{
+						{Name: "web-7d4b9c-x2x4z", Node: "node-1", OwnerKind: "Deployment", OwnerName: "web"},
+						{Name: "db-0", Node: "node-2", OwnerKind: "StatefulSet", OwnerName: "db"},
+						{Name: "standalone", Node: "node-3"},
+					},
+				},
+			})), ctrl
+		},
+		"success without pods, attachments and claims when listing them is denied": func(*testing.T) (k8s.VolumeFinder, []checkFn, *gomock.Controller) {
+			ctrl := gomock.NewController(t)
+			api := mocks.NewMockVolumeGetter(ctrl)
+
+			volumes := &corev1.PersistentVolumeList{
+				Items: []corev1.PersistentVolume{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "persistent-volume-name"},
+						Spec: corev1.PersistentVolumeSpec{
+							PersistentVolumeSource: corev1.PersistentVolumeSource{
+								CSI: &corev1.CSIPersistentVolumeSource{Driver: "csi-vxflexos.dellemc.com"},
+							},
+							ClaimRef: &corev1.ObjectReference{Name: "pvc-name", Namespace: "namespace-1"},
+						},
+					},
+				},
+			}
+
+			api.EXPECT().GetPersistentVolumes().Times(1).Return(volumes, nil)
+			denied := fmt.Errorf("pods %w: forbidden", k8s.ErrListDenied)
+			api.EXPECT().GetPods().Times(1).Return(nil, denied)
+			api.EXPECT().GetVolumeAttachments().Times(1).Return(nil, denied)
+			api.EXPECT().GetPersistentVolumeClaims().Times(1).Return(nil, denied)
+
+			finder := k8s.VolumeFinder{
+				API:         api,
+				DriverNames: []string{"csi-vxflexos.dellemc.com"},
+				Logger:      logrus.New(),
+			}
+			return finder, check(hasNoError, func(t *testing.T, volumes []k8s.VolumeInfo, _ error) {
+				assert.Equal(t, 1, len(volumes))
+				assert.Nil(t, volumes[0].Pods)
+				assert.Empty(t, volumes[0].AttachedNode)
+			}), ctrl
+		},
+		"success resolving the attachments of each volume": func(*testing.T) (k8s.VolumeFinder, []checkFn, *gomock.Controller) {
+			ctrl := gomock.NewController(t)
+			api := mocks.NewMockVolumeGetter(ctrl)
+
+			volume := func(name string) corev1.PersistentVolume {
+				return corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: corev1.PersistentVolumeSpec{
+						PersistentVolumeSource: corev1.PersistentVolumeSource{
+							CSI: &corev1.CSIPersistentVolumeSource{Driver: "csi-powerstore.dellemc.com"},
+						},
+						ClaimRef: &corev1.ObjectReference{Name: "pvc-" + name, Namespace: "namespace-1"},
+					},
+				}
+			}
+			volumes := &corev1.PersistentVolumeList{
+				Items: []corev1.PersistentVolume{volume("attached"), volume("failed"), volume("migrating"), volume("shared"), volume("detached")},
+			}
+
+			attachment := func(volume, node string, status storagev1.VolumeAttachmentStatus) storagev1.VolumeAttachment {
+				return storagev1.VolumeAttachment{
+					Spec: storagev1.VolumeAttachmentSpec{
+						NodeName: node,
+						Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &volume},
+					},
+					Status: status,
+				}
+			}
+			deleted := attachment("migrating", "node-1", storagev1.VolumeAttachmentStatus{Attached: true})
+			deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			attachments := &storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{
+					attachment("attached", "node-1", storagev1.VolumeAttachmentStatus{Attached: true}),
+					attachment("failed", "node-2", storagev1.VolumeAttachmentStatus{
+						AttachError: &storagev1.VolumeError{Message: "host not found on array"},
+					}),
+					deleted,
+					attachment("migrating", "node-2", storagev1.VolumeAttachmentStatus{
+						AttachError: &storagev1.VolumeError{Message: "timed out"},
+					}),
+					attachment("shared", "node-1", storagev1.VolumeAttachmentStatus{Attached: true}),
+					attachment("shared", "node-2", storagev1.VolumeAttachmentStatus{Attached: true}),
+				},
+			}
+
+			api.EXPECT().GetPersistentVolumes().Times(1).Return(volumes, nil)
+			api.EXPECT().GetPods().Times(1).Return(&corev1.PodList{}, nil)
+			api.EXPECT().GetReplicaSets().Times(1).Return(&appsv1.ReplicaSetList{}, nil)
+			api.EXPECT().GetVolumeAttachments().Times(1).Return(attachments, nil)
+			api.EXPECT().GetPersistentVolumeClaims().Times(1).Return(&corev1.PersistentVolumeClaimList{}, nil)
+			api.EXPECT().GetStorageClasses().Times(1).Return(&storagev1.StorageClassList{}, nil)
+
+			finder := k8s.VolumeFinder{
+				API:         api,
+				DriverNames: []string{"csi-powerstore.dellemc.com"},
+				Logger:      logrus.New(),
+			}
+			return finder, check(hasNoError, func(t *testing.T, volumes []k8s.VolumeInfo, _ error) {
+				type attachInfo struct{ node, status, err string }
+				result := make(map[string]attachInfo)
+				for _, volume := range volumes {
+					result[volume.PersistentVolume] = attachInfo{volume.AttachedNode, volume.AttachStatus, volume.AttachError}
+				}
+				assert.Equal(t, map[string]attachInfo{
+					"attached":  {"node-1", "Attached", ""},
+					"failed":    {"node-2", "Attaching", "host not found on array"},
+					"migrating": {"node-1, node-2", "Detaching, Attaching", "node-2: timed out"},
+					"shared":    {"node-1, node-2", "Attached, Attached", ""},
+					"detached":  {"", "", ""},
+				}, result)
+			}), ctrl
+		},
+		"success adding the pending claims of the driver storage classes": func(*testing.T) (k8s.VolumeFinder, []checkFn, *gomock.Controller) {
+			ctrl := gomock.NewController(t)
+			api := mocks.NewMockVolumeGetter(ctrl)
+
+			t1, err := time.Parse(time.RFC3339, "2020-07-28T20:00:00+00:00")
+			assert.Nil(t, err)
+			t2 := t1.Add(time.Minute)
+
+			powerstore, other := "powerstore", "other"
+			claim := func(name string, class *string, phase corev1.PersistentVolumeClaimPhase) corev1.PersistentVolumeClaim {
+				return corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              name,
+						Namespace:         "namespace-1",
+						UID:               types.UID(name + "-uid"),
+						CreationTimestamp: metav1.Time{Time: t1},
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						StorageClassName: class,
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{v1.ResourceStorage: resource.MustParse("8Gi")},
+						},
+					},
+					Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+				}
+			}
+			claims := &corev1.PersistentVolumeClaimList{
+				Items: []corev1.PersistentVolumeClaim{
+					claim("pending", &powerstore, corev1.ClaimPending),
+					claim("default-class", nil, corev1.ClaimPending),
+					claim("bound", &powerstore, corev1.ClaimBound),
+					claim("other-driver", &other, corev1.ClaimPending),
+				},
+			}
+			classes := &storagev1.StorageClassList{
+				Items: []storagev1.StorageClass{
+					{
+						ObjectMeta:  metav1.ObjectMeta{Name: "powerstore", Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}},
+						Provisioner: "csi-powerstore.dellemc.com",
+					},
+					{ObjectMeta: metav1.ObjectMeta{Name: "other"}, Provisioner: "other.csi.k8s.io"},
+				},
+			}
+			events := &corev1.EventList{
+				Items: []corev1.Event{
+					{
+						InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", UID: "pending-uid"},
+						LastTimestamp:  metav1.Time{Time: t2},
+						Message:        "failed to provision volume: pool is full",
+					},
+					{
+						InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", UID: "pending-uid"},
+						LastTimestamp:  metav1.Time{Time: t1},
+						Message:        "waiting for a volume to be created",
+					},
+				},
+			}
+
+			api.EXPECT().GetPersistentVolumes().Times(1).Return(&corev1.PersistentVolumeList{}, nil)
+			api.EXPECT().GetPods().Times(1).Return(&corev1.PodList{}, nil)
+			api.EXPECT().GetReplicaSets().Times(1).Return(&appsv1.ReplicaSetList{}, nil)
+			api.EXPECT().GetVolumeAttachments().Times(1).Return(&storagev1.VolumeAttachmentList{}, nil)
+			api.EXPECT().GetPersistentVolumeClaims().Times(1).Return(claims, nil)
+			api.EXPECT().GetStorageClasses().Times(1).Return(classes, nil)
+			api.EXPECT().GetPersistentVolumeClaimEvents().Times(1).Return(events, nil)
+
+			finder := k8s.VolumeFinder{
+				API:         api,
+				DriverNames: []string{"csi-powerstore.dellemc.com"},
+				Logger:      logrus.New(),
+			}
+			return finder, check(hasNoError, checkExpectedOutput([]k8s.VolumeInfo{
+				{
+					Namespace:              "namespace-1",
+					PersistentVolumeClaim:  "pending-uid",
+					PersistentVolumeStatus: "Unbound",
+					VolumeClaimName:        "pending",
+					StorageClass:           "powerstore",
+					Driver:                 "csi-powerstore.dellemc.com",
+					ProvisionedSize:        "8Gi",
+					CreatedTime:            t1.String(),
+					Message:                "failed to provision volume: pool is full",
+				},
+				{
+					Namespace:              "namespace-1",
+					PersistentVolumeClaim:  "default-class-uid",
+					PersistentVolumeStatus: "Unbound",
+					VolumeClaimName:        "default-class",
+					StorageClass:           "powerstore",
+					Driver:                 "csi-powerstore.dellemc.com",
+					ProvisionedSize:        "8Gi",
+					CreatedTime:            t1.String(),
+				},
+			})), ctrl
+		},
+		"error while the pod cache has not synced": func(*testing.T) (k8s.VolumeFinder, []checkFn, *gomock.Controller) {
+			ctrl := gomock.NewController(t)
+			api := mocks.NewMockVolumeGetter(ctrl)
+			api.EXPECT().GetPersistentVolumes().Times(1).Return(&corev1.PersistentVolumeList{}, nil)
+			api.EXPECT().GetPods().Times(1).Return(nil, fmt.Errorf("pods %w", k8s.ErrCacheNotSynced))
+			finder := k8s.VolumeFinder{
+				API:         api,
+				DriverNames: []string{"csi-vxflexos.dellemc.com"},
+				Logger:      logrus.New(),
+			}
+			return finder, check(hasError, func(t *testing.T, _ []k8s.VolumeInfo, err error) {
+				assert.ErrorIs(t, err, k8s.ErrCacheNotSynced)
+			}), ctrl
+		},
 		"error calling k8s": func(*testing.T) (k8s.VolumeFinder, []checkFn, *gomock.Controller) {
 			ctrl := gomock.NewController(t)
 			api := mocks.NewMockVolumeGetter(ctrl)
@@ -403,3 +771,15 @@ func Test_K8sPersistentVolumeFinder(t *testing.T) {
 		})
 	}
 }
+
+func Test_VolumeFinderReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	api := mocks.NewMockVolumeGetter(ctrl)
+	api.EXPECT().HasSynced().Times(1).Return(false)
+	api.EXPECT().HasSynced().Times(1).Return(true)
+
+	finder := k8s.VolumeFinder{API: api, Logger: logrus.New()}
+	assert.False(t, finder.Ready())
+	assert.True(t, finder.Ready())
+}
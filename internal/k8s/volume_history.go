@@ -0,0 +1,232 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Types of volume events
+const (
+	VolumeAdded   = "Added"
+	VolumeUpdated = "Updated"
+	VolumeDeleted = "Deleted"
+)
+
+// VolumeEvent is a change of a persistent volume observed by the persistent volume informer
+type VolumeEvent struct {
+	Time                  time.Time `json:"time"`
+	Type                  string    `json:"type"`
+	PersistentVolume      string    `json:"persistent_volume"`
+	Namespace             string    `json:"namespace"`
+	PersistentVolumeClaim string    `json:"persistent_volume_claim"`
+	Driver                string    `json:"csi_driver"`
+	StorageClass          string    `json:"storage_class"`
+	Capacity              string    `json:"capacity"`
+	Status                string    `json:"status"`
+	// PreviousStatus is the status before an update
+	PreviousStatus string `json:"previous_status"`
+	// Changes describes what an update changed, e.g. "status: Bound -> Released"
+	Changes []string `json:"changes"`
+	Cluster string   `json:"cluster"`
+}
+
+// VolumeHistory is a bounded, time-ordered log of volume events. Events older than the retention window are dropped
+// and, when the log holds more than MaxEvents events, the oldest ones are dropped first.
+type VolumeHistory struct {
+	// Now returns the current time; time.Now is used when it is nil
+	Now func() time.Time
+
+	lock      sync.RWMutex
+	events    []VolumeEvent
+	retention time.Duration
+	maxEvents int
+}
+
+// NewVolumeHistory returns an empty history keeping the events of the retention window, at most maxEvents of them.
+// No events are recorded when retention is not positive and the number of events is not bounded when maxEvents is not
+// positive.
+func NewVolumeHistory(retention time.Duration, maxEvents int) *VolumeHistory {
+	return &VolumeHistory{retention: retention, maxEvents: maxEvents}
+}
+
+// SetRetention changes the retention window and the maximum number of events, dropping the events they exclude
+func (h *VolumeHistory) SetRetention(retention time.Duration, maxEvents int) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.retention = retention
+	h.maxEvents = maxEvents
+	h.prune()
+}
+
+// Record adds an event to the history, keeping the history ordered by time
+func (h *VolumeHistory) Record(event VolumeEvent) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.retention <= 0 {
+		return
+	}
+	i := sort.Search(len(h.events), func(i int) bool { return h.events[i].Time.After(event.Time) })
+	h.events = append(h.events, VolumeEvent{})
+	copy(h.events[i+1:], h.events[i:])
+	h.events[i] = event
+	h.prune()
+}
+
+// GetVolumeEvents returns the events that happened at or after since, oldest first
+func (h *VolumeHistory) GetVolumeEvents(since time.Time) []VolumeEvent {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.prune()
+	i := sort.Search(len(h.events), func(i int) bool { return !h.events[i].Time.Before(since) })
+	return append([]VolumeEvent{}, h.events[i:]...)
+}
+
+// prune drops the events outside the retention window and the oldest events above the maximum.
+// The caller must hold h.lock.
+func (h *VolumeHistory) prune() {
+	if h.retention <= 0 {
+		h.events = nil
+		return
+	}
+	cutoff := h.now().Add(-h.retention)
+	start := sort.Search(len(h.events), func(i int) bool { return !h.events[i].Time.Before(cutoff) })
+	if h.maxEvents > 0 && len(h.events)-start > h.maxEvents {
+		start = len(h.events) - h.maxEvents
+	}
+	if start > 0 {
+		h.events = append([]VolumeEvent{}, h.events[start:]...)
+	}
+}
+
+// now returns the current time
+func (h *VolumeHistory) now() time.Time {
+	if h.Now == nil {
+		return time.Now()
+	}
+	return h.Now()
+}
+
+// VolumeEventHandler returns the persistent volume informer handler recording the changes of the volumes of the
+// finder's drivers in history. The volumes listed when the informer starts are not recorded, and updates are only
+// recorded when the status, claim, capacity or deletion of the volume changes. The drivers are selected with the
+// settings in use when each event is received, so reloading the configuration applies to the next events.
+func (f *VolumeFinder) VolumeEventHandler(history *VolumeHistory) cache.ResourceEventHandler {
+	record := func(eventType string, volume *corev1.PersistentVolume, changes []string, previousStatus string) {
+		if volume.Spec.CSI == nil || !f.configuredDrivers()(volume.Spec.CSI.Driver) {
+			return
+		}
+		event := newVolumeEvent(eventType, volume, history.now())
+		event.Cluster = f.Cluster
+		event.Changes = changes
+		event.PreviousStatus = previousStatus
+		history.Record(event)
+	}
+
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if volume, ok := obj.(*corev1.PersistentVolume); ok && !isInInitialList {
+				record(VolumeAdded, volume, nil, "")
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldVolume, okOld := oldObj.(*corev1.PersistentVolume)
+			newVolume, okNew := newObj.(*corev1.PersistentVolume)
+			if !okOld || !okNew {
+				return
+			}
+			if changes := volumeChanges(oldVolume, newVolume); len(changes) > 0 {
+				record(VolumeUpdated, newVolume, changes, string(oldVolume.Status.Phase))
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if volume, ok := obj.(*corev1.PersistentVolume); ok {
+				record(VolumeDeleted, volume, nil, "")
+			}
+		},
+	}
+}
+
+// newVolumeEvent returns an event describing the current state of a volume
+func newVolumeEvent(eventType string, volume *corev1.PersistentVolume, now time.Time) VolumeEvent {
+	event := VolumeEvent{
+		Time:             now,
+		Type:             eventType,
+		PersistentVolume: volume.Name,
+		StorageClass:     volume.Spec.StorageClassName,
+		Capacity:         volumeCapacity(volume),
+		Status:           string(volume.Status.Phase),
+	}
+	if volume.Spec.CSI != nil {
+		event.Driver = volume.Spec.CSI.Driver
+	}
+	if claim := volume.Spec.ClaimRef; claim != nil {
+		event.Namespace = claim.Namespace
+		event.PersistentVolumeClaim = claim.Name
+	}
+	return event
+}
+
+// volumeChanges describes the changes of a volume that are recorded in the history
+func volumeChanges(oldVolume, newVolume *corev1.PersistentVolume) []string {
+	changes := make([]string, 0)
+	change := func(what, before, after string) {
+		if before != after {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", what, orNone(before), orNone(after)))
+		}
+	}
+	change("status", string(oldVolume.Status.Phase), string(newVolume.Status.Phase))
+	change("claim", claimName(oldVolume.Spec.ClaimRef), claimName(newVolume.Spec.ClaimRef))
+	change("capacity", volumeCapacity(oldVolume), volumeCapacity(newVolume))
+	if oldVolume.DeletionTimestamp == nil && newVolume.DeletionTimestamp != nil {
+		changes = append(changes, "deletion requested")
+	}
+	return changes
+}
+
+// volumeCapacity returns the storage capacity of a volume, or an empty string when it is not set
+func volumeCapacity(volume *corev1.PersistentVolume) string {
+	if capacity, ok := volume.Spec.Capacity[corev1.ResourceStorage]; ok {
+		return capacity.String()
+	}
+	return ""
+}
+
+// claimName returns the namespace/name of a claim reference, or an empty string for unbound volumes
+func claimName(claim *corev1.ObjectReference) string {
+	if claim == nil {
+		return ""
+	}
+	return claim.Namespace + "/" + claim.Name
+}
+
+// orNone returns value, or "none" when it is empty
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,164 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package k8s_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_VolumeHistory(t *testing.T) {
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	names := func(events []k8s.VolumeEvent) []string {
+		result := make([]string, 0, len(events))
+		for _, event := range events {
+			result = append(result, event.PersistentVolume)
+		}
+		return result
+	}
+
+	now := at(0)
+	history := k8s.NewVolumeHistory(time.Hour, 3)
+	history.Now = func() time.Time { return now }
+
+	history.Record(k8s.VolumeEvent{Time: at(-10), PersistentVolume: "pv-2"})
+	history.Record(k8s.VolumeEvent{Time: at(-20), PersistentVolume: "pv-1"})
+	history.Record(k8s.VolumeEvent{Time: at(-5), PersistentVolume: "pv-3"})
+	assert.Equal(t, []string{"pv-1", "pv-2", "pv-3"}, names(history.GetVolumeEvents(time.Time{})))
+	assert.Equal(t, []string{"pv-2", "pv-3"}, names(history.GetVolumeEvents(at(-10))))
+	assert.Empty(t, history.GetVolumeEvents(at(1)))
+
+	// the oldest events are dropped above the maximum
+	history.Record(k8s.VolumeEvent{Time: at(0), PersistentVolume: "pv-4"})
+	assert.Equal(t, []string{"pv-2", "pv-3", "pv-4"}, names(history.GetVolumeEvents(time.Time{})))
+
+	// events leave the retention window as time passes
+	now = at(52)
+	assert.Equal(t, []string{"pv-3", "pv-4"}, names(history.GetVolumeEvents(time.Time{})))
+
+	history.SetRetention(30*time.Minute, 0)
+	assert.Empty(t, history.GetVolumeEvents(time.Time{}))
+	for i := 0; i < 5; i++ {
+		history.Record(k8s.VolumeEvent{Time: now, PersistentVolume: "pv-5"})
+	}
+	assert.Len(t, history.GetVolumeEvents(time.Time{}), 5)
+
+	// a zero retention disables the history
+	history.SetRetention(0, 0)
+	history.Record(k8s.VolumeEvent{Time: now, PersistentVolume: "pv-6"})
+	assert.Empty(t, history.GetVolumeEvents(time.Time{}))
+}
+
+func Test_VolumeEventHandler(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	volume := func(driver string, phase corev1.PersistentVolumePhase, claim string, capacity string) *corev1.PersistentVolume {
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:               corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+				PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: driver}},
+				StorageClassName:       "powerstore",
+			},
+			Status: corev1.PersistentVolumeStatus{Phase: phase},
+		}
+		if claim != "" {
+			pv.Spec.ClaimRef = &corev1.ObjectReference{Namespace: "ns-1", Name: claim}
+		}
+		return pv
+	}
+	const driver = "csi-powerstore.dellemc.com"
+
+	history := k8s.NewVolumeHistory(time.Hour, 0)
+	history.Now = func() time.Time { return now }
+	finder := &k8s.VolumeFinder{DriverNames: []string{driver}, Cluster: "east", Logger: logrus.New()}
+	handler := finder.VolumeEventHandler(history)
+
+	bound := volume(driver, corev1.VolumeBound, "pvc-1", "8Gi")
+	handler.OnAdd(bound, true)
+	assert.Empty(t, history.GetVolumeEvents(time.Time{}), "the initial list is not recorded")
+
+	handler.OnAdd(bound, false)
+	handler.OnUpdate(bound, bound.DeepCopy())
+	handler.OnAdd(volume("csi-other.example.com", corev1.VolumeBound, "pvc-2", "1Gi"), false)
+
+	released := volume(driver, corev1.VolumeReleased, "", "16Gi")
+	handler.OnUpdate(bound, released)
+	deleting := released.DeepCopy()
+	deleting.DeletionTimestamp = &metav1.Time{Time: now}
+	handler.OnUpdate(released, deleting)
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "pv-1", Obj: deleting})
+
+	assert.Equal(t, []k8s.VolumeEvent{
+		{
+			Time: now, Type: k8s.VolumeAdded, PersistentVolume: "pv-1", Namespace: "ns-1", PersistentVolumeClaim: "pvc-1",
+			Driver: driver, StorageClass: "powerstore", Capacity: "8Gi", Status: "Bound", Cluster: "east",
+		},
+		{
+			Time: now, Type: k8s.VolumeUpdated, PersistentVolume: "pv-1", Driver: driver, StorageClass: "powerstore",
+			Capacity: "16Gi", Status: "Released", PreviousStatus: "Bound", Cluster: "east",
+			Changes: []string{"status: Bound -> Released", "claim: ns-1/pvc-1 -> none", "capacity: 8Gi -> 16Gi"},
+		},
+		{
+			Time: now, Type: k8s.VolumeUpdated, PersistentVolume: "pv-1", Driver: driver, StorageClass: "powerstore",
+			Capacity: "16Gi", Status: "Released", PreviousStatus: "Released", Cluster: "east",
+			Changes: []string{"deletion requested"},
+		},
+		{
+			Time: now, Type: k8s.VolumeDeleted, PersistentVolume: "pv-1", Driver: driver, StorageClass: "powerstore",
+			Capacity: "16Gi", Status: "Released", Cluster: "east",
+		},
+	}, history.GetVolumeEvents(time.Time{}))
+}
+
+func Test_VolumeEventHandlerReadsSettingsPerEvent(t *testing.T) {
+	volume := func(name, driver string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: driver}}},
+		}
+	}
+
+	history := k8s.NewVolumeHistory(time.Hour, 0)
+	finder := &k8s.VolumeFinder{Logger: logrus.New()}
+	finder.SetSettings(k8s.DriverSettings{DriverNames: []string{"csi-powerstore.dellemc.com"}})
+	handler := finder.VolumeEventHandler(history)
+
+	handler.OnAdd(volume("pv-1", "csi-powerstore.dellemc.com"), false)
+	handler.OnAdd(volume("pv-2", "csi-unity.dellemc.com"), false)
+
+	// reloading the configuration applies to the next events
+	finder.SetSettings(k8s.DriverSettings{DriverNames: []string{"csi-unity.dellemc.com"}})
+	handler.OnAdd(volume("pv-3", "csi-powerstore.dellemc.com"), false)
+	handler.OnAdd(volume("pv-4", "csi-unity.dellemc.com"), false)
+
+	var recorded []string
+	for _, event := range history.GetVolumeEvents(time.Time{}) {
+		recorded = append(recorded, event.PersistentVolume)
+	}
+	assert.Equal(t, []string{"pv-1", "pv-4"}, recorded)
+}
@@ -0,0 +1,287 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregation
+//
+// A target with the reserved key "$groupBy" or "$aggregate" returns one row per distinct combination of the
+// grouped columns instead of one row per volume. The filters of the target select the rows that are aggregated, among
+// the rows the table aggregates: unbound claims are left out of the volume table aggregations.
+//
+//	"$groupBy": "Storage System"                   group by a column
+//	"$groupBy": ["Storage System", "Namespace"]    group by several columns, in order
+//	"$aggregate": ["count", "sum"]                 aggregates to compute, all of them by default
+//
+// Without "$groupBy" all rows are aggregated in a single group. The aggregates are
+//
+//	count   the number of volumes
+//	sum     the total provisioned size in bytes
+//	min     the smallest provisioned size in bytes
+//	max     the largest provisioned size in bytes
+//	avg     the average provisioned size in bytes
+//
+// For example {"$groupBy": ["Storage System", "Namespace"], "$aggregate": ["count", "sum"]} returns the volume count
+// and capacity per array per namespace. Table targets get a table with the grouped columns followed by one column
+// per aggregate; time series targets get one series per group and aggregate.
+
+// Reserved top-level keys of aggregation targets
+const (
+	groupByKey   = "$groupBy"
+	aggregateKey = "$aggregate"
+)
+
+// Aggregates
+const (
+	aggregateCount = "count"
+	aggregateSum   = "sum"
+	aggregateMin   = "min"
+	aggregateMax   = "max"
+	aggregateAvg   = "avg"
+)
+
+// aggregateNames lists the aggregates in the order of their columns
+var aggregateNames = []string{aggregateCount, aggregateSum, aggregateMin, aggregateMax, aggregateAvg}
+
+// aggregateColumns describes the column of each aggregate
+var aggregateColumns = map[string]tableColumn{
+	aggregateCount: {Key: "volumes", Text: "Volumes", Type: columnNumber},
+	aggregateSum:   {Key: "total_provisioned_size", Text: "Total Provisioned Size", Type: columnNumber},
+	aggregateMin:   {Key: "min_provisioned_size", Text: "Min Provisioned Size", Type: columnNumber},
+	aggregateMax:   {Key: "max_provisioned_size", Text: "Max Provisioned Size", Type: columnNumber},
+	aggregateAvg:   {Key: "avg_provisioned_size", Text: "Avg Provisioned Size", Type: columnNumber},
+}
+
+// aggregation is a parsed aggregation target
+type aggregation struct {
+	// groupBy holds the indexes of the grouped columns
+	groupBy []int
+	// aggregates holds the aggregates to compute, in column order
+	aggregates []string
+	// size is the index of the number column the size aggregates are computed over
+	size int
+}
+
+// group accumulates the rows of one group
+type group struct {
+	values []string
+	count  int
+	sized  int
+	sum    int64
+	min    int64
+	max    int64
+}
+
+// parseAggregation removes the aggregation keys from the fields of a target and parses them; it returns nil when the
+// target is not an aggregation
+func (schema tableSchema) parseAggregation(fields map[string]json.RawMessage) (*aggregation, error) {
+	groupBy, grouped := fields[groupByKey]
+	aggregates, aggregated := fields[aggregateKey]
+	delete(fields, groupByKey)
+	delete(fields, aggregateKey)
+	if !grouped && !aggregated {
+		return nil, nil
+	}
+
+	a := &aggregation{aggregates: aggregateNames, size: -1}
+	for i, column := range schema {
+		if column.Type == columnNumber {
+			a.size = i
+			break
+		}
+	}
+
+	if grouped {
+		names, err := parseNames(groupBy)
+		if err != nil {
+			return nil, fmt.Errorf("%q %v", groupByKey, err)
+		}
+		for _, name := range names {
+			index, err := schema.column(name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", groupByKey, err)
+			}
+			a.groupBy = append(a.groupBy, index)
+		}
+	}
+
+	if aggregated {
+		names, err := parseNames(aggregates)
+		if err != nil {
+			return nil, fmt.Errorf("%q %v", aggregateKey, err)
+		}
+		requested := make(map[string]bool, len(names))
+		for _, name := range names {
+			name = strings.ToLower(name)
+			if _, ok := aggregateColumns[name]; !ok {
+				return nil, fmt.Errorf("unknown aggregate %q; valid aggregates are %s", name, strings.Join(aggregateNames, ", "))
+			}
+			requested[name] = true
+		}
+		a.aggregates = make([]string, 0, len(requested))
+		for _, name := range aggregateNames {
+			if requested[name] {
+				a.aggregates = append(a.aggregates, name)
+			}
+		}
+		if len(a.aggregates) == 0 {
+			return nil, fmt.Errorf("%q must name at least one aggregate", aggregateKey)
+		}
+	}
+	return a, nil
+}
+
+// parseNames parses a string or a list of strings
+func parseNames(data json.RawMessage) ([]string, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return []string{name}, nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, errors.New("must be a string or a list of strings")
+	}
+	return names, nil
+}
+
+// aggregate groups the rows and returns the schema and rows of the aggregated table, sorted by the grouped columns
+func (schema tableSchema) aggregate(a *aggregation, rows [][]string) (tableSchema, [][]string) {
+	result := make(tableSchema, 0, len(a.groupBy)+len(a.aggregates))
+	for _, index := range a.groupBy {
+		result = append(result, schema[index])
+	}
+	for _, name := range a.aggregates {
+		result = append(result, aggregateColumns[name])
+	}
+
+	groups := make(map[string]*group)
+	order := make([]*group, 0)
+	if len(a.groupBy) == 0 {
+		// a single group that exists even without rows, so that counts are reported as zero
+		groups[""] = &group{}
+		order = append(order, groups[""])
+	}
+	for _, row := range rows {
+		values := make([]string, 0, len(a.groupBy))
+		for _, index := range a.groupBy {
+			values = append(values, row[index])
+		}
+		key := strings.Join(values, "\x00")
+		g, ok := groups[key]
+		if !ok {
+			g = &group{values: values}
+			groups[key] = g
+			order = append(order, g)
+		}
+		g.add(a.size, row)
+	}
+
+	aggregated := make([][]string, 0, len(order))
+	for _, g := range order {
+		row := append([]string{}, g.values...)
+		for _, name := range a.aggregates {
+			row = append(row, g.value(name))
+		}
+		aggregated = append(aggregated, row)
+	}
+
+	keys := make([]sortKey, 0, len(a.groupBy))
+	for i := range a.groupBy {
+		keys = append(keys, sortKey{index: i})
+	}
+	result.sortRows(keys, aggregated)
+	return result, aggregated
+}
+
+// add accumulates a row into the group; rows without a parsable size are only counted
+func (g *group) add(size int, row []string) {
+	g.count++
+	if size < 0 {
+		return
+	}
+	bytes, ok := parseBytes(row[size])
+	if !ok {
+		return
+	}
+	if g.sized == 0 || bytes < g.min {
+		g.min = bytes
+	}
+	if g.sized == 0 || bytes > g.max {
+		g.max = bytes
+	}
+	g.sum += bytes
+	g.sized++
+}
+
+// value returns the raw value of an aggregate; size aggregates of a group without sizes are empty
+func (g *group) value(name string) string {
+	switch name {
+	case aggregateCount:
+		return strconv.Itoa(g.count)
+	case aggregateSum:
+		return strconv.FormatInt(g.sum, 10)
+	}
+	if g.sized == 0 {
+		return ""
+	}
+	switch name {
+	case aggregateMin:
+		return strconv.FormatInt(g.min, 10)
+	case aggregateMax:
+		return strconv.FormatInt(g.max, 10)
+	default:
+		return strconv.FormatInt(g.sum/int64(g.sized), 10)
+	}
+}
+
+// series returns one Grafana time series per group and aggregate of an aggregated table, named after the group values
+// and, when several aggregates are computed, the aggregate. The single group of an aggregation without groups is named
+// after the rows.
+func (a *aggregation) series(refID string, name string, schema tableSchema, rows [][]string) []interface{} {
+	now := float64(time.Now().UnixMilli())
+	series := make([]interface{}, 0, len(rows)*len(a.aggregates))
+	for _, row := range rows {
+		group := strings.Join(row[:len(a.groupBy)], " / ")
+		if group == "" {
+			group = name
+		}
+		for i := len(a.groupBy); i < len(schema); i++ {
+			value, ok := parseBytes(row[i])
+			if !ok {
+				continue
+			}
+			target := group
+			if len(a.aggregates) > 1 {
+				target = fmt.Sprintf("%s %s", group, schema[i].Text)
+			}
+			series = append(series, timeSeries{
+				RefID:      refID,
+				Target:     target,
+				Datapoints: [][]float64{{float64(value), now}},
+			})
+		}
+	}
+	return series
+}
@@ -0,0 +1,139 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gib = float64(1024 * 1024 * 1024)
+
+func TestQueryAggregationTable(t *testing.T) {
+	tests := map[string]struct {
+		target          string
+		expectedStatus  int
+		expectedColumns []string
+		expectedRows    [][]interface{}
+	}{
+		"all aggregates by protocol": {
+			target:          `{"$groupBy": "Protocol"}`,
+			expectedStatus:  http.StatusOK,
+			expectedColumns: []string{"Protocol", "Volumes", "Total Provisioned Size", "Min Provisioned Size", "Max Provisioned Size", "Avg Provisioned Size"},
+			expectedRows:    [][]interface{}{{"scsi", float64(2), 24 * gib, 8 * gib, 16 * gib, 12 * gib}},
+		},
+		"capacity per array per namespace": {
+			target:          `{"$groupBy": ["storage_system", "Namespace"], "$aggregate": ["sum", "count"]}`,
+			expectedStatus:  http.StatusOK,
+			expectedColumns: []string{"Storage System", "Namespace", "Volumes", "Total Provisioned Size"},
+			expectedRows: [][]interface{}{
+				{"10.0.0.1", "ns-1", float64(1), 8 * gib},
+				{"7045c4cc20dffc0f", "ns-2", float64(1), 16 * gib},
+			},
+		},
+		"filtered rows": {
+			target:          `{"$groupBy": "Namespace", "$aggregate": "max", "Status": "Bound"}`,
+			expectedStatus:  http.StatusOK,
+			expectedColumns: []string{"Namespace", "Max Provisioned Size"},
+			expectedRows:    [][]interface{}{{"ns-1", 8 * gib}},
+		},
+		"single group without rows": {
+			target:          `{"$aggregate": ["count", "avg"], "Namespace": "ns-3"}`,
+			expectedStatus:  http.StatusOK,
+			expectedColumns: []string{"Volumes", "Avg Provisioned Size"},
+			expectedRows:    [][]interface{}{{float64(0), nil}},
+		},
+		"unknown group column": {
+			target:         `{"$groupBy": "color"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown aggregate": {
+			target:         `{"$aggregate": "median"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"invalid group by": {
+			target:         `{"$groupBy": 1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"no aggregates": {
+			target:         `{"$aggregate": []}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"targets": [{"target": %q, "refId": "A", "type": "table"}]}`, tc.target)
+			status, frames := queryFrames(t, body)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, 1, len(frames))
+			columns := make([]string, 0)
+			for _, column := range frames[0].Columns {
+				columns = append(columns, column.Text)
+			}
+			assert.Equal(t, tc.expectedColumns, columns)
+			assert.Equal(t, tc.expectedRows, frames[0].Rows)
+		})
+	}
+}
+
+func TestQueryAggregationTimeSeries(t *testing.T) {
+	tests := map[string]struct {
+		target          string
+		expectedTargets []string
+		expectedValues  []float64
+	}{
+		"count per namespace": {
+			target:          `{"$groupBy": "Namespace", "$aggregate": "count"}`,
+			expectedTargets: []string{"ns-1", "ns-2"},
+			expectedValues:  []float64{1, 1},
+		},
+		"several aggregates": {
+			target:          `{"$groupBy": "Protocol", "$aggregate": ["count", "sum"]}`,
+			expectedTargets: []string{"scsi Volumes", "scsi Total Provisioned Size"},
+			expectedValues:  []float64{2, 24 * gib},
+		},
+		"without groups": {
+			target:          `{"$aggregate": "sum"}`,
+			expectedTargets: []string{"volumes"},
+			expectedValues:  []float64{24 * gib},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"targets": [{"target": %q, "refId": "A", "type": "timeserie"}]}`, tc.target)
+			status, frames := queryFrames(t, body)
+			assert.Equal(t, http.StatusOK, status)
+
+			targets := make([]string, 0)
+			values := make([]float64, 0)
+			for _, frame := range frames {
+				assert.Equal(t, "A", frame.RefID)
+				targets = append(targets, frame.Target)
+				values = append(values, frame.Datapoints[0][0])
+			}
+			assert.Equal(t, tc.expectedTargets, targets)
+			assert.Equal(t, tc.expectedValues, values)
+		})
+	}
+}
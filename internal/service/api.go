@@ -0,0 +1,535 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	tracer "github.com/dell/karavi-topology/internal/tracers"
+	"github.com/gorilla/mux"
+)
+
+// Reserved query string parameters of the REST API; every other parameter is a column filter
+const (
+	sortParam     = "sort"
+	limitParam    = "limit"
+	continueParam = "continue"
+	fieldsParam   = "fields"
+)
+
+// errContinueMismatch is returned for continue tokens issued for another list request
+var errContinueMismatch = errors.New("continue token was issued for other filters or sort")
+
+// listResponse is the response of a REST API list request
+type listResponse[T any] struct {
+	Items []T `json:"items"`
+	// Continue is the token to pass as the continue parameter to get the next page; empty on the last page
+	Continue string `json:"continue,omitempty"`
+	// Total is the number of items matching the filters across all pages
+	Total int `json:"total"`
+}
+
+// sortKey is a column to sort by
+type sortKey struct {
+	index      int
+	descending bool
+}
+
+// listQuery is a parsed REST API list request
+type listQuery struct {
+	filter rowFilter
+	sort   []sortKey
+	offset int
+	limit  int
+	fields []int
+	// digest identifies the filters and sort of the request in its continue tokens
+	digest string
+	// anchor is carried by the continue tokens, so that later pages select the rows as the first page did, e.g. with
+	// the instant a relative since resolved to
+	anchor string
+}
+
+// itemFunc returns the REST API item of the row at index, restricted to the fields
+type itemFunc[T any] func(fields []int, rows [][]string, index int) T
+
+// volumeItem is a persistent volume of the REST API
+type volumeItem struct {
+	Namespace               string      `json:"namespace"`
+	PersistentVolume        string      `json:"persistent_volume"`
+	Status                  string      `json:"status"`
+	PersistentVolumeClaim   string      `json:"persistent_volume_claim"`
+	CSIDriver               string      `json:"csi_driver"`
+	Created                 *time.Time  `json:"created"`
+	ProvisionedSize         *int64      `json:"provisioned_size"`
+	StorageClass            string      `json:"storage_class"`
+	StorageSystemVolumeName string      `json:"storage_system_volume_name"`
+	StoragePool             string      `json:"storage_pool"`
+	StorageSystem           string      `json:"storage_system"`
+	Protocol                string      `json:"protocol"`
+	Pods                    []volumePod `json:"pods"`
+	AttachedNode            string      `json:"attached_node"`
+	AttachStatus            string      `json:"attach_status"`
+	AttachError             string      `json:"attach_error"`
+	Age                     string      `json:"age"`
+	Message                 string      `json:"message"`
+	Cluster                 string      `json:"cluster"`
+	// keys are the JSON keys of the requested fields
+	keys map[string]bool
+}
+
+// volumePod is a pod mounting a persistent volume of the REST API
+type volumePod struct {
+	Name      string `json:"name"`
+	Node      string `json:"node"`
+	OwnerKind string `json:"owner_kind"`
+	Owner     string `json:"owner"`
+}
+
+// podColumns are the volume columns listing the pods of a volume, served together as the pods of a volumeItem
+var podColumns = []string{"pod", "owner_kind", "owner", "node"}
+
+// newVolumeItem returns the REST API item of a volume restricted to the fields
+func newVolumeItem(volume k8s.VolumeInfo, fields []int) volumeItem {
+	item := volumeItem{
+		Namespace:               volume.Namespace,
+		PersistentVolume:        volume.PersistentVolume,
+		Status:                  volume.PersistentVolumeStatus,
+		PersistentVolumeClaim:   volume.VolumeClaimName,
+		CSIDriver:               volume.Driver,
+		StorageClass:            volume.StorageClass,
+		StorageSystemVolumeName: volume.StorageSystemVolumeName,
+		StoragePool:             volume.StoragePoolName,
+		StorageSystem:           volume.StorageSystem,
+		Protocol:                volume.Protocol,
+		Pods:                    make([]volumePod, 0, len(volume.Pods)),
+		AttachedNode:            volume.AttachedNode,
+		AttachStatus:            volume.AttachStatus,
+		AttachError:             volume.AttachError,
+		Age:                     age(volume.CreatedTime),
+		Message:                 volume.Message,
+		Cluster:                 volume.Cluster,
+		keys:                    make(map[string]bool, len(fields)),
+	}
+	if t, ok := parseTime(volume.CreatedTime); ok {
+		t = t.UTC()
+		item.Created = &t
+	}
+	if size, ok := parseBytes(volume.ProvisionedSize); ok {
+		item.ProvisionedSize = &size
+	}
+	for _, pod := range volume.Pods {
+		item.Pods = append(item.Pods, volumePod{Name: pod.Name, Node: pod.Node, OwnerKind: pod.OwnerKind, Owner: pod.OwnerName})
+	}
+	for _, index := range fields {
+		key := volumeSchema[index].Key
+		if k8s.Contains(podColumns, key) {
+			key = "pods"
+		}
+		item.keys[key] = true
+	}
+	return item
+}
+
+// MarshalJSON implements json.Marshaler, writing the requested fields only
+func (item volumeItem) MarshalJSON() ([]byte, error) {
+	type fields volumeItem
+	data, err := json.Marshal(fields(item))
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for key := range all {
+		if !item.keys[key] {
+			delete(all, key)
+		}
+	}
+	return json.Marshal(all)
+}
+
+// listVolumesRequest returns the volumes matching the query string filters
+func (s *Service) listVolumesRequest(w http.ResponseWriter, r *http.Request) {
+	table, item := s.volumeItems()
+	listTable(s, w, r, table, item)
+}
+
+// getVolumeRequest returns the volume with the persistent volume name given in the path
+func (s *Service) getVolumeRequest(w http.ResponseWriter, r *http.Request) {
+	table, item := s.volumeItems()
+	getTableRow(s, w, r, table, item)
+}
+
+// volumeItems returns the volume table with the function returning the REST API item of its rows. The rows are
+// loaded together with their volumes, so that items are built from the volumes rather than from the columns.
+func (s *Service) volumeItems() (topologyTable, itemFunc[volumeItem]) {
+	var volumes []k8s.VolumeInfo
+	table := s.volumeTable()
+	table.rows = func(ctx context.Context) ([][]string, error) {
+		var err error
+		if volumes, err = s.VolumeFinder.GetPersistentVolumes(ctx); err != nil {
+			return nil, err
+		}
+		return volumeRows(volumes), nil
+	}
+	return table, func(fields []int, _ [][]string, index int) volumeItem {
+		return newVolumeItem(volumes[index], fields)
+	}
+}
+
+// listTable returns the items of the rows of a table matching the query string filters
+func listTable[T any](s *Service, w http.ResponseWriter, r *http.Request, table topologyTable, item itemFunc[T]) {
+	ctx, span := tracer.GetTracer(context.Background(), "listRequest")
+	defer span.End()
+
+	format, err := responseFormat(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		s.Logger.WithError(err).Error("negotiating response format")
+		return
+	}
+
+	query, err := table.schema.parseListQuery(r.URL.Query(), table.params...)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		s.Logger.WithError(err).Errorf("parsing %s list query", table.name)
+		return
+	}
+	query.anchor = table.anchor
+
+	rows, err := table.rows(ctx)
+	if err != nil {
+		s.writeError(w, errorStatus(err), fmt.Errorf("getting %s", table.name))
+		s.Logger.WithError(err).Errorf("getting %s", table.name)
+		return
+	}
+
+	page := table.schema.page(query, rows)
+	list := listResponse[T]{Items: make([]T, 0, len(page.indexes)), Continue: page.next, Total: page.total}
+	for _, index := range page.indexes {
+		list.Items = append(list.Items, item(query.fields, rows, index))
+	}
+	if format != formatJSON {
+		writeListExport(s, w, format, table, query.fields, rows, page, list.Items)
+		return
+	}
+	s.writeJSON(w, list)
+}
+
+// getTableRow returns the item of the row of a table whose key column equals the name given in the path; query string
+// filters, such as the cluster, pick the row when the name is not unique
+func getTableRow[T any](s *Service, w http.ResponseWriter, r *http.Request, table topologyTable, item itemFunc[T]) {
+	ctx, span := tracer.GetTracer(context.Background(), "getRequest")
+	defer span.End()
+
+	name := mux.Vars(r)["name"]
+	query, err := table.schema.parseListQuery(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		s.Logger.WithError(err).Errorf("parsing %s query", table.item)
+		return
+	}
+
+	rows, err := table.rows(ctx)
+	if err != nil {
+		s.writeError(w, errorStatus(err), fmt.Errorf("getting %s", table.name))
+		s.Logger.WithError(err).Errorf("getting %s", table.name)
+		return
+	}
+
+	for index, row := range rows {
+		if row[table.key] == name && query.filter.match(row) {
+			s.writeJSON(w, item(query.fields, rows, index))
+			return
+		}
+	}
+	s.writeError(w, http.StatusNotFound, fmt.Errorf("%s %q not found", table.item, name))
+}
+
+// parseListQuery parses the query string of a REST API list request. The params are the parameters of the request
+// that are not filters; they are part of the continue token digest like the filters.
+func (schema tableSchema) parseListQuery(values url.Values, params ...string) (listQuery, error) {
+	query := listQuery{}
+	var err error
+	if query.filter, err = schema.parseFilters(values, params...); err != nil {
+		return listQuery{}, err
+	}
+
+	for _, name := range splitList(values.Get(sortParam)) {
+		key := sortKey{}
+		if strings.HasPrefix(name, "-") {
+			key.descending = true
+			name = strings.TrimPrefix(name, "-")
+		}
+		if key.index, err = schema.column(name); err != nil {
+			return listQuery{}, fmt.Errorf("invalid sort: %v", err)
+		}
+		query.sort = append(query.sort, key)
+	}
+
+	if limit := values.Get(limitParam); limit != "" {
+		if query.limit, err = strconv.Atoi(limit); err != nil || query.limit < 1 {
+			return listQuery{}, fmt.Errorf("invalid limit %q: must be a positive integer", limit)
+		}
+	}
+
+	query.digest = listDigest(values)
+	if token := values.Get(continueParam); token != "" {
+		if query.offset, err = decodeContinue(token, query.digest); errors.Is(err, errContinueMismatch) {
+			return listQuery{}, err
+		} else if err != nil {
+			return listQuery{}, fmt.Errorf("invalid continue token %q", token)
+		}
+	}
+
+	for _, name := range splitList(values.Get(fieldsParam)) {
+		index, err := schema.column(name)
+		if err != nil {
+			return listQuery{}, fmt.Errorf("invalid fields: %v", err)
+		}
+		query.fields = append(query.fields, index)
+	}
+	if len(query.fields) == 0 {
+		for i := range schema {
+			query.fields = append(query.fields, i)
+		}
+	}
+	return query, nil
+}
+
+// parseFilters parses the column filters of a query string, skipping the list parameters and the params
+func (schema tableSchema) parseFilters(values url.Values, params ...string) (rowFilter, error) {
+	fields := make(map[string]json.RawMessage)
+	for key, value := range values {
+		switch key {
+		case sortParam, limitParam, continueParam, fieldsParam, formatParam:
+			continue
+		}
+		if slices.Contains(params, key) {
+			continue
+		}
+		var data []byte
+		if len(value) == 1 {
+			data, _ = json.Marshal(value[0])
+		} else {
+			data, _ = json.Marshal(value)
+		}
+		fields[key] = data
+	}
+	return schema.parseFields(fields)
+}
+
+// listPage is a page of a REST API list
+type listPage struct {
+	// indexes are the indexes of the rows of the page, in list order
+	indexes []int
+	// next is the continue token of the next page; empty on the last page
+	next string
+	// total is the number of rows matching the filters across all pages
+	total int
+}
+
+// page filters, sorts and paginates the rows
+func (schema tableSchema) page(query listQuery, rows [][]string) listPage {
+	indexes := make([]int, 0, len(rows))
+	for index, row := range rows {
+		if query.filter.match(row) {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return schema.less(query.sort, rows[indexes[i]], rows[indexes[j]])
+	})
+
+	page := listPage{indexes: []int{}, total: len(indexes)}
+	if query.offset >= len(indexes) {
+		return page
+	}
+	end := len(indexes)
+	if query.limit > 0 && query.offset+query.limit < end {
+		end = query.offset + query.limit
+		page.next = encodeContinue(end, query.digest, query.anchor)
+	}
+	page.indexes = indexes[query.offset:end]
+	return page
+}
+
+// sortRows sorts rows by the sort keys, see less
+func (schema tableSchema) sortRows(keys []sortKey, rows [][]string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return schema.less(keys, rows[i], rows[j])
+	})
+}
+
+// less orders rows by the sort keys, comparing typed columns by value; rows that compare equal are ordered by their
+// columns from left to right so that pages are stable
+func (schema tableSchema) less(keys []sortKey, a, b []string) bool {
+	for _, key := range keys {
+		c := schema.compare(key.index, a[key.index], b[key.index])
+		if c != 0 {
+			return (c < 0) != key.descending
+		}
+	}
+	for index := range schema {
+		if c := strings.Compare(a[index], b[index]); c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// compare compares two values of the column at index according to the column type
+func (schema tableSchema) compare(index int, a, b string) int {
+	switch schema[index].Type {
+	case columnNumber:
+		x, okX := parseBytes(a)
+		y, okY := parseBytes(b)
+		if okX && okY {
+			return cmpInt64(x, y)
+		}
+	case columnTime:
+		x, okX := parseTime(a)
+		y, okY := parseTime(b)
+		if okX && okY {
+			return x.Compare(y)
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// rowItem returns the fields of the row at index as a JSON object keyed by column name
+func (schema tableSchema) rowItem(fields []int, rows [][]string, index int) map[string]interface{} {
+	return schema.item(fields, rows[index])
+}
+
+// item returns the fields of a row as a JSON object keyed by column name
+func (schema tableSchema) item(fields []int, row []string) map[string]interface{} {
+	item := make(map[string]interface{}, len(fields))
+	for _, index := range fields {
+		column := schema[index]
+		item[column.Key] = jsonValue(column.Type, row[index])
+	}
+	return item
+}
+
+// fieldTitles returns the titles of the fields
+func (schema tableSchema) fieldTitles(fields []int) []string {
+	titles := make([]string, 0, len(fields))
+	for _, index := range fields {
+		titles = append(titles, schema[index].Text)
+	}
+	return titles
+}
+
+// jsonValue converts a raw column value to its REST API representation: sizes in bytes and times in RFC 3339
+func jsonValue(columnType, value string) interface{} {
+	if columnType == columnTime {
+		t, ok := parseTime(value)
+		if !ok {
+			return nil
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return typedValue(columnType, value)
+}
+
+// splitList splits a comma separated parameter, ignoring empty entries
+func splitList(value string) []string {
+	list := make([]string, 0)
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// listDigest returns the digest of the filters and sort of a list request; the limit and the fields do not change
+// which rows come before a page, so they can change between pages
+func listDigest(values url.Values) string {
+	selection := make(url.Values, len(values))
+	for key, value := range values {
+		switch key {
+		case limitParam, continueParam, fieldsParam, formatParam:
+			continue
+		}
+		selection[key] = value
+	}
+	sum := sha256.Sum256([]byte(selection.Encode()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// encodeContinue returns the continue token of the page starting at offset of the list request with the digest,
+// carrying the anchor when there is one
+func encodeContinue(offset int, digest, anchor string) string {
+	token := strconv.Itoa(offset) + "." + digest
+	if anchor != "" {
+		token += "." + anchor
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(token))
+}
+
+// continueAnchor returns the anchor carried by a continue token, or "" when there is none; the token is checked
+// against the request when the list query is parsed
+func continueAnchor(token string) string {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ""
+	}
+	parts := strings.SplitN(string(data), ".", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[2]
+}
+
+// decodeContinue returns the offset of a continue token, or errContinueMismatch when the token was issued for a list
+// request with another digest
+func decodeContinue(token, digest string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	value, tokenDigest, ok := strings.Cut(string(data), ".")
+	if !ok {
+		return 0, errors.New("missing digest")
+	}
+	tokenDigest, _, _ = strings.Cut(tokenDigest, ".")
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, errors.New("invalid offset")
+	}
+	if tokenDigest != digest {
+		return 0, errContinueMismatch
+	}
+	return offset, nil
+}
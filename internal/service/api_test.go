@@ -0,0 +1,445 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testList struct {
+	Items    []map[string]interface{} `json:"items"`
+	Continue string                   `json:"continue"`
+	Total    int                      `json:"total"`
+}
+
+func get(t *testing.T, url string) (int, []byte) {
+	res, err := http.Get(url)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	assert.Nil(t, err)
+	return res.StatusCode, body
+}
+
+func TestListVolumes(t *testing.T) {
+	tests := map[string]struct {
+		query           string
+		expectedStatus  int
+		expectedVolumes []string
+		expectedTotal   int
+		hasContinue     bool
+	}{
+		"all volumes": {
+			query:           "",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1", "pv-2"},
+			expectedTotal:   2,
+		},
+		"filtered": {
+			query:           "?namespace=ns-2",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+			expectedTotal:   1,
+		},
+		"filter operators": {
+			query:           "?provisioned_size=%3E10Gi&status=!%3DBound",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+			expectedTotal:   1,
+		},
+		"repeated filter": {
+			query:           "?namespace=ns-1&namespace=ns-2",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1", "pv-2"},
+			expectedTotal:   2,
+		},
+		"sorted descending by size": {
+			query:           "?sort=-provisioned_size",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2", "pv-1"},
+			expectedTotal:   2,
+		},
+		"sorted by created": {
+			query:           "?sort=created",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1", "pv-2"},
+			expectedTotal:   2,
+		},
+		"first page": {
+			query:           "?limit=1&sort=-namespace",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+			expectedTotal:   2,
+			hasContinue:     true,
+		},
+		"unknown filter": {
+			query:          "?color=blue",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown sort": {
+			query:          "?sort=color",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"invalid limit": {
+			query:          "?limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"invalid continue": {
+			query:          "?continue=!!",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"continue without digest": {
+			query:          "?limit=1&continue=MQ",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown field": {
+			query:          "?fields=color",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).AnyTimes().Return(testVolumes(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := get(t, ctx.server.URL+"/api/v1/volumes"+tc.query)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var list testList
+			assert.Nil(t, json.Unmarshal(body, &list))
+			volumes := make([]string, 0)
+			for _, item := range list.Items {
+				volumes = append(volumes, item["persistent_volume"].(string))
+			}
+			assert.Equal(t, tc.expectedVolumes, volumes)
+			assert.Equal(t, tc.expectedTotal, list.Total)
+			assert.Equal(t, tc.hasContinue, list.Continue != "")
+		})
+	}
+}
+
+func TestListVolumesPages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).AnyTimes().Return(testVolumes(), nil)
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	list := func(query string) (int, testList) {
+		status, body := get(t, ctx.server.URL+"/api/v1/volumes"+query)
+		var list testList
+		if status == http.StatusOK {
+			assert.Nil(t, json.Unmarshal(body, &list))
+		}
+		return status, list
+	}
+
+	status, first := list("?limit=1&sort=-namespace&namespace=%7Bns-1,ns-2%7D")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "pv-2", first.Items[0]["persistent_volume"])
+	assert.NotEmpty(t, first.Continue)
+
+	status, second := list("?namespace=%7Bns-1,ns-2%7D&sort=-namespace&limit=5&fields=persistent_volume&continue=" + first.Continue)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []map[string]interface{}{{"persistent_volume": "pv-1"}}, second.Items)
+	assert.Equal(t, 2, second.Total)
+	assert.Empty(t, second.Continue)
+
+	status, _ = list("?limit=1&sort=namespace&namespace=%7Bns-1,ns-2%7D&continue=" + first.Continue)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = list("?limit=1&sort=-namespace&namespace=ns-1&continue=" + first.Continue)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestListVolumesSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(3).Return(testVolumes(), nil)
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	status, body := get(t, ctx.server.URL+"/api/v1/volumes?namespace=ns-1")
+	assert.Equal(t, http.StatusOK, status)
+	var list testList
+	assert.Nil(t, json.Unmarshal(body, &list))
+	assert.NotEmpty(t, list.Items[0]["age"])
+	delete(list.Items[0], "age")
+	assert.Equal(t, map[string]interface{}{
+		"namespace":                  "ns-1",
+		"persistent_volume":          "pv-1",
+		"status":                     "Bound",
+		"persistent_volume_claim":    "pvc-1",
+		"csi_driver":                 "csi-powerstore.dellemc.com",
+		"created":                    "2020-07-28T20:00:00Z",
+		"provisioned_size":           float64(8589934592),
+		"storage_class":              "powerstore",
+		"storage_system_volume_name": "pv-1",
+		"storage_pool":               "N/A",
+		"storage_system":             "10.0.0.1",
+		"protocol":                   "scsi",
+		"pods": []interface{}{
+			map[string]interface{}{"name": "web-0", "node": "node-1", "owner_kind": "StatefulSet", "owner": "web"},
+			map[string]interface{}{"name": "web-1", "node": "node-2", "owner_kind": "StatefulSet", "owner": "web"},
+		},
+		"attached_node": "node-1",
+		"attach_status": "Attached",
+		"attach_error":  "",
+		"message":       "",
+		"cluster":       "",
+	}, list.Items[0])
+
+	status, body = get(t, ctx.server.URL+"/api/v1/volumes?fields=persistent_volume,Storage%20System")
+	assert.Equal(t, http.StatusOK, status)
+	var projected testList
+	assert.Nil(t, json.Unmarshal(body, &projected))
+	assert.Equal(t, map[string]interface{}{"persistent_volume": "pv-1", "storage_system": "10.0.0.1"}, projected.Items[0])
+
+	status, body = get(t, ctx.server.URL+"/api/v1/volumes?fields=persistent_volume,node&namespace=ns-2")
+	assert.Equal(t, http.StatusOK, status)
+	var pods testList
+	assert.Nil(t, json.Unmarshal(body, &pods))
+	assert.Equal(t, map[string]interface{}{"persistent_volume": "pv-2", "pods": []interface{}{}}, pods.Items[0])
+}
+
+func TestListVolumesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(nil, errors.New("error"))
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	status, _ := get(t, ctx.server.URL+"/api/v1/volumes")
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
+
+func TestListVolumesNotSynced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(nil, k8s.ErrCacheNotSynced)
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	status, _ := get(t, ctx.server.URL+"/api/v1/volumes")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+}
+
+func TestGetVolume(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		expectedStatus int
+		expected       map[string]interface{}
+	}{
+		"found": {
+			path:           "/api/v1/volumes/pv-2?fields=namespace,provisioned_size",
+			expectedStatus: http.StatusOK,
+			expected:       map[string]interface{}{"namespace": "ns-2", "provisioned_size": float64(17179869184)},
+		},
+		"not found": {
+			path:           "/api/v1/volumes/pv-3",
+			expectedStatus: http.StatusNotFound,
+			expected:       map[string]interface{}{"message": "persistent volume \"pv-3\" not found"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(testVolumes(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := get(t, ctx.server.URL+tc.path)
+			assert.Equal(t, tc.expectedStatus, status)
+			var result map[string]interface{}
+			assert.Nil(t, json.Unmarshal(body, &result))
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestVolumesFilterValuesAreExact(t *testing.T) {
+	prefixVolumes := func() []k8s.VolumeInfo {
+		volumes := testVolumes()
+		volumes[0].Namespace = "ns"
+		volumes[0].PersistentVolume = "pv-a"
+		volumes[1].Namespace = "ns-1"
+		volumes[1].PersistentVolume = "pv-ab"
+		return volumes
+	}
+	tests := map[string]struct {
+		path            string
+		expectedStatus  int
+		expectedVolumes []string
+	}{
+		"value extending a column": {
+			path:            "/api/v1/volumes?namespace=ns-1",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-ab"},
+		},
+		"value prefix of a column": {
+			path:            "/api/v1/volumes?namespace=ns",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-a"},
+		},
+		"multi-value variable": {
+			path:            "/api/v1/volumes?namespace=%7Bns-1,ns-3%7D",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-ab"},
+		},
+		"substring of the volume name": {
+			path:            "/api/v1/volumes?persistent_volume=pv",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{},
+		},
+		"volume in another namespace": {
+			path:           "/api/v1/volumes/pv-a?namespace=ns-1",
+			expectedStatus: http.StatusNotFound,
+		},
+		"volume in its namespace": {
+			path:            "/api/v1/volumes/pv-a?namespace=ns",
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-a"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(prefixVolumes(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := get(t, ctx.server.URL+tc.path)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			var list testList
+			assert.Nil(t, json.Unmarshal(body, &list))
+			if list.Items == nil {
+				var item map[string]interface{}
+				assert.Nil(t, json.Unmarshal(body, &item))
+				list.Items = append(list.Items, item)
+			}
+			volumes := make([]string, 0)
+			for _, item := range list.Items {
+				volumes = append(volumes, item["persistent_volume"].(string))
+			}
+			assert.Equal(t, tc.expectedVolumes, volumes)
+		})
+	}
+}
+
+func TestClusterVolumes(t *testing.T) {
+	clusterVolumes := func() []k8s.VolumeInfo {
+		volumes := append(testVolumes(), testVolumes()[0])
+		volumes[0].Cluster = "east"
+		volumes[1].Cluster = "east"
+		volumes[2].Cluster = "west"
+		volumes[2].Namespace = "ns-3"
+		return volumes
+	}
+
+	tests := map[string]struct {
+		path           string
+		expectedStatus int
+		expected       interface{}
+	}{
+		"list filtered by cluster": {
+			path:           "/api/v1/volumes?cluster=west&fields=cluster,persistent_volume,namespace",
+			expectedStatus: http.StatusOK,
+			expected: testList{
+				Items: []map[string]interface{}{{"cluster": "west", "persistent_volume": "pv-1", "namespace": "ns-3"}},
+				Total: 1,
+			},
+		},
+		"get picks the first cluster": {
+			path:           "/api/v1/volumes/pv-1?fields=cluster,namespace",
+			expectedStatus: http.StatusOK,
+			expected:       map[string]interface{}{"cluster": "east", "namespace": "ns-1"},
+		},
+		"get with a cluster filter": {
+			path:           "/api/v1/volumes/pv-1?cluster=west&fields=cluster,namespace",
+			expectedStatus: http.StatusOK,
+			expected:       map[string]interface{}{"cluster": "west", "namespace": "ns-3"},
+		},
+		"get in another cluster": {
+			path:           "/api/v1/volumes/pv-2?cluster=west",
+			expectedStatus: http.StatusNotFound,
+			expected:       map[string]interface{}{"message": "persistent volume \"pv-2\" not found"},
+		},
+		"get with an unknown filter": {
+			path:           "/api/v1/volumes/pv-1?region=west",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).AnyTimes().Return(clusterVolumes(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := get(t, ctx.server.URL+tc.path)
+			assert.Equal(t, tc.expectedStatus, status)
+			switch expected := tc.expected.(type) {
+			case testList:
+				var list testList
+				assert.Nil(t, json.Unmarshal(body, &list))
+				assert.Equal(t, expected, list)
+			case map[string]interface{}:
+				var result map[string]interface{}
+				assert.Nil(t, json.Unmarshal(body, &result))
+				assert.Equal(t, expected, result)
+			}
+		})
+	}
+}
+
+func TestVolumesMethodNotAllowed(t *testing.T) {
+	ctx, teardown := setup(nil)
+	defer teardown()
+
+	status, _ := post(t, ctx.server.URL+"/api/v1/volumes", "{}")
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+}
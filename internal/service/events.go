@@ -0,0 +1,207 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	tracer "github.com/dell/karavi-topology/internal/tracers"
+)
+
+// sinceParam is the query string parameter of the events request selecting the events that happened at or after it
+const sinceParam = "since"
+
+// errHistoryDisabled is returned by the history endpoints when the service has no change history
+var errHistoryDisabled = errors.New("the change history is not enabled")
+
+// eventSchema describes the columns of the volume event table
+var eventSchema = tableSchema{
+	{Key: "time", Text: "Time", Type: columnTime},
+	{Key: "type", Text: "Type", Type: columnString},
+	{Key: "persistent_volume", Text: "Persistent Volume", Type: columnString},
+	{Key: "namespace", Text: "Namespace", Type: columnString},
+	{Key: "persistent_volume_claim", Text: "Persistent Volume Claim", Type: columnString},
+	{Key: "csi_driver", Text: "CSI Driver", Type: columnString},
+	{Key: "storage_class", Text: "Storage Class", Type: columnString},
+	{Key: "capacity", Text: "Capacity", Type: columnNumber},
+	{Key: "status", Text: "Status", Type: columnString},
+	{Key: "previous_status", Text: "Previous Status", Type: columnString},
+	{Key: "changes", Text: "Changes", Type: columnString},
+	{Key: "cluster", Text: "Cluster", Type: columnString},
+}
+
+// eventRow returns the raw values of a volume event in eventSchema order
+func eventRow(event k8s.VolumeEvent) []string {
+	return []string{
+		event.Time.UTC().Format(createdTimeLayout),
+		event.Type,
+		event.PersistentVolume,
+		event.Namespace,
+		event.PersistentVolumeClaim,
+		event.Driver,
+		event.StorageClass,
+		event.Capacity,
+		event.Status,
+		event.PreviousStatus,
+		strings.Join(event.Changes, "; "),
+		event.Cluster,
+	}
+}
+
+// eventTable returns the table of the volume events that happened at or after since
+func (s *Service) eventTable(since time.Time) topologyTable {
+	key, _ := eventSchema.column("persistent_volume")
+	return topologyTable{
+		name:   "events",
+		item:   "volume event",
+		file:   "events",
+		schema: eventSchema,
+		key:    key,
+		rows: func(_ context.Context) ([][]string, error) {
+			events := s.VolumeHistory.GetVolumeEvents(since)
+			rows := make([][]string, 0, len(events))
+			for _, event := range events {
+				rows = append(rows, eventRow(event))
+			}
+			return rows, nil
+		},
+	}
+}
+
+// listEventsRequest returns the volume events that happened at or after the since parameter, oldest first unless
+// sorted otherwise, and matching the query string filters
+func (s *Service) listEventsRequest(w http.ResponseWriter, r *http.Request) {
+	if s.VolumeHistory == nil {
+		s.writeError(w, http.StatusNotFound, errHistoryDisabled)
+		return
+	}
+
+	values := r.URL.Query()
+	since, err := resolveSince(values)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		s.Logger.WithError(err).Error("parsing events query")
+		return
+	}
+	if values.Get(sortParam) == "" {
+		values.Set(sortParam, "time")
+	}
+
+	r = r.Clone(r.Context())
+	r.URL.RawQuery = values.Encode()
+	table := s.eventTable(since)
+	table.params = []string{sinceParam}
+	if !since.IsZero() {
+		table.anchor = strconv.FormatInt(since.UnixNano(), 10)
+	}
+	listTable(s, w, r, table, table.schema.rowItem)
+}
+
+// resolveSince returns the instant of the since parameter of an events request. Relative times are resolved on the
+// first page only: later pages use the instant carried by their continue token, so that the pages of a list do not
+// shift as time passes.
+func resolveSince(values url.Values) (time.Time, error) {
+	value := values.Get(sinceParam)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if anchor := continueAnchor(values.Get(continueParam)); anchor != "" {
+		nanos, err := strconv.ParseInt(anchor, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid continue token %q", values.Get(continueParam))
+		}
+		return time.Unix(0, nanos).UTC(), nil
+	}
+	since, err := parseTimeOperand(value, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: %v", sinceParam, value, err)
+	}
+	return since, nil
+}
+
+// eventAnnotationsRequest returns the volume events of the dashboard time range that match the annotation query
+func (s *Service) eventAnnotationsRequest(w http.ResponseWriter, r *http.Request) {
+	_, span := tracer.GetTracer(context.Background(), "eventAnnotationsRequest")
+	defer span.End()
+
+	if s.VolumeHistory == nil {
+		s.writeError(w, http.StatusNotFound, errHistoryDisabled)
+		return
+	}
+
+	var requestBody struct {
+		Range      timeRange `json:"range"`
+		Annotation struct {
+			Name  string `json:"name"`
+			Query string `json:"query"`
+		} `json:"annotation"`
+	}
+	if err := DecodeBodyFn(r.Body, &requestBody); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		s.Logger.WithError(err).Error("decoding annotations body")
+		return
+	}
+
+	query, err := eventSchema.parseTarget(requestBody.Annotation.Query)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("annotation query: %v", err))
+		s.Logger.WithError(err).Errorf("parsing annotation query: %s", requestBody.Annotation.Query)
+		return
+	}
+
+	annotations := make([]annotation, 0)
+	for _, event := range s.VolumeHistory.GetVolumeEvents(requestBody.Range.From) {
+		if !requestBody.Range.contains(event.Time) || !query.filter.match(eventRow(event)) {
+			continue
+		}
+		tags := make([]string, 0, 4)
+		for _, tag := range []string{strings.ToLower(event.Type), event.Namespace, event.Driver, event.Cluster} {
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		annotations = append(annotations, annotation{
+			Annotation: requestBody.Annotation,
+			Time:       event.Time.UnixMilli(),
+			Title:      fmt.Sprintf("Volume %s %s", event.PersistentVolume, strings.ToLower(event.Type)),
+			Text:       eventText(event),
+			Tags:       tags,
+		})
+	}
+	s.writeJSON(w, annotations)
+}
+
+// eventText describes a volume event: what an update changed, or the state of an added or deleted volume
+func eventText(event k8s.VolumeEvent) string {
+	if len(event.Changes) > 0 {
+		return strings.Join(event.Changes, ", ")
+	}
+	text := fmt.Sprintf("%s %s by %s", event.Capacity, event.Status, event.Driver)
+	if event.PersistentVolumeClaim != "" {
+		text += fmt.Sprintf(", claimed by %s/%s", event.Namespace, event.PersistentVolumeClaim)
+	}
+	return text
+}
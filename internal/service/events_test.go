@@ -0,0 +1,259 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEvents() []k8s.VolumeEvent {
+	t1, _ := time.Parse(time.RFC3339, "2026-10-16T10:00:00Z")
+	return []k8s.VolumeEvent{
+		{
+			Time: t1, Type: k8s.VolumeAdded, PersistentVolume: "pv-1", Namespace: "ns-1", PersistentVolumeClaim: "pvc-1",
+			Driver: "csi-powerstore.dellemc.com", StorageClass: "powerstore", Capacity: "8Gi", Status: "Bound",
+		},
+		{
+			Time: t1.Add(time.Hour), Type: k8s.VolumeUpdated, PersistentVolume: "pv-1", Driver: "csi-powerstore.dellemc.com",
+			StorageClass: "powerstore", Capacity: "8Gi", Status: "Released", PreviousStatus: "Bound",
+			Changes: []string{"status: Bound -> Released", "claim: ns-1/pvc-1 -> none"},
+		},
+		{
+			Time: t1.Add(2 * time.Hour), Type: k8s.VolumeDeleted, PersistentVolume: "pv-2", Namespace: "ns-2",
+			PersistentVolumeClaim: "pvc-2", Driver: "csi-vxflexos.dellemc.com", StorageClass: "vxflexos", Capacity: "16Gi",
+			Status: "Bound", Cluster: "east",
+		},
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	since, _ := time.Parse(time.RFC3339, "2026-10-16T10:30:00Z")
+	tests := map[string]struct {
+		query          string
+		since          interface{}
+		expectedStatus int
+		expectedEvents []string
+	}{
+		"all events": {
+			query:          "",
+			since:          time.Time{},
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{"Added pv-1", "Updated pv-1", "Deleted pv-2"},
+		},
+		"since": {
+			query:          "?since=2026-10-16T10:30:00Z",
+			since:          since,
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{"Added pv-1", "Updated pv-1", "Deleted pv-2"},
+		},
+		"relative since": {
+			query:          "?since=now-1h",
+			since:          gomock.Any(),
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{"Added pv-1", "Updated pv-1", "Deleted pv-2"},
+		},
+		"filtered and sorted": {
+			query:          "?persistent_volume=pv-1&sort=-time",
+			since:          time.Time{},
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{"Updated pv-1", "Added pv-1"},
+		},
+		"filtered by status change": {
+			query:          "?previous_status=Bound&status=Released",
+			since:          time.Time{},
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{"Updated pv-1"},
+		},
+		"invalid since": {
+			query:          "?since=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown filter": {
+			query:          "?color=blue",
+			since:          time.Time{},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			history := mocks.NewMockVolumeEventGetter(ctrl)
+			if tc.since != nil {
+				history.EXPECT().GetVolumeEvents(tc.since).AnyTimes().Return(testEvents())
+			}
+
+			ctx, teardown := setup(nil)
+			defer teardown()
+			ctx.svc.VolumeHistory = history
+
+			status, body := get(t, ctx.server.URL+"/api/v1/events"+tc.query)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var list testList
+			assert.Nil(t, json.Unmarshal(body, &list))
+			events := make([]string, 0)
+			for _, item := range list.Items {
+				events = append(events, item["type"].(string)+" "+item["persistent_volume"].(string))
+			}
+			assert.Equal(t, tc.expectedEvents, events)
+		})
+	}
+}
+
+func TestListEventsPages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	history := mocks.NewMockVolumeEventGetter(ctrl)
+	var since []time.Time
+	history.EXPECT().GetVolumeEvents(gomock.Any()).AnyTimes().DoAndReturn(func(t time.Time) []k8s.VolumeEvent {
+		since = append(since, t)
+		return testEvents()
+	})
+
+	ctx, teardown := setup(nil)
+	defer teardown()
+	ctx.svc.VolumeHistory = history
+
+	list := func(query string) (int, testList) {
+		status, body := get(t, ctx.server.URL+"/api/v1/events"+query)
+		var list testList
+		if status == http.StatusOK {
+			assert.Nil(t, json.Unmarshal(body, &list))
+		}
+		return status, list
+	}
+
+	status, first := list("?since=now-1h&limit=2")
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotEmpty(t, first.Continue)
+
+	time.Sleep(10 * time.Millisecond)
+	status, second := list("?since=now-1h&limit=2&continue=" + first.Continue)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "pv-2", second.Items[0]["persistent_volume"])
+	assert.Equal(t, 3, second.Total)
+	assert.Len(t, since, 2)
+	assert.True(t, since[0].Equal(since[1]), "the relative since is resolved on the first page only")
+
+	status, _ = list("?since=now-2h&limit=2&continue=" + first.Continue)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = list("?limit=2&continue=" + first.Continue)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestListEventsSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	history := mocks.NewMockVolumeEventGetter(ctrl)
+	history.EXPECT().GetVolumeEvents(gomock.Any()).Times(1).Return(testEvents())
+
+	ctx, teardown := setup(nil)
+	defer teardown()
+	ctx.svc.VolumeHistory = history
+
+	status, body := get(t, ctx.server.URL+"/api/v1/events?type=Updated")
+	assert.Equal(t, http.StatusOK, status)
+	var list testList
+	assert.Nil(t, json.Unmarshal(body, &list))
+	assert.Equal(t, []map[string]interface{}{{
+		"time":                    "2026-10-16T11:00:00Z",
+		"type":                    "Updated",
+		"persistent_volume":       "pv-1",
+		"namespace":               "",
+		"persistent_volume_claim": "",
+		"csi_driver":              "csi-powerstore.dellemc.com",
+		"storage_class":           "powerstore",
+		"capacity":                float64(8589934592),
+		"status":                  "Released",
+		"previous_status":         "Bound",
+		"changes":                 "status: Bound -> Released; claim: ns-1/pvc-1 -> none",
+		"cluster":                 "",
+	}}, list.Items)
+}
+
+func TestEventsDisabled(t *testing.T) {
+	ctx, teardown := setup(nil)
+	defer teardown()
+
+	status, body := get(t, ctx.server.URL+"/api/v1/events")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.JSONEq(t, `{"message": "the change history is not enabled"}`, string(body))
+
+	status, _ = post(t, ctx.server.URL+"/events/annotations", "{}")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestEventAnnotations(t *testing.T) {
+	from, _ := time.Parse(time.RFC3339, "2026-10-16T10:30:00Z")
+	tests := map[string]struct {
+		body           string
+		since          time.Time
+		expectedStatus int
+		expected       string
+	}{
+		"events in range": {
+			body:           `{"range": {"from": "2026-10-16T10:30:00Z", "to": "2026-10-16T11:30:00Z"}, "annotation": {"name": "changes"}}`,
+			since:          from,
+			expectedStatus: http.StatusOK,
+			expected: `[{"annotation": {"name": "changes", "query": ""}, "time": 1792148400000, "title": "Volume pv-1 updated",
+				"text": "status: Bound -> Released, claim: ns-1/pvc-1 -> none", "tags": ["updated", "csi-powerstore.dellemc.com"]}]`,
+		},
+		"filtered by query": {
+			body:           `{"annotation": {"query": "{\"Type\": [\"Added\", \"Deleted\"]}"}}`,
+			expectedStatus: http.StatusOK,
+			expected: `[
+				{"annotation": {"name": "", "query": "{\"Type\": [\"Added\", \"Deleted\"]}"}, "time": 1792144800000,
+				"title": "Volume pv-1 added", "text": "8Gi Bound by csi-powerstore.dellemc.com, claimed by ns-1/pvc-1",
+				"tags": ["added", "ns-1", "csi-powerstore.dellemc.com"]},
+				{"annotation": {"name": "", "query": "{\"Type\": [\"Added\", \"Deleted\"]}"}, "time": 1792152000000,
+				"title": "Volume pv-2 deleted", "text": "16Gi Bound by csi-vxflexos.dellemc.com, claimed by ns-2/pvc-2",
+				"tags": ["deleted", "ns-2", "csi-vxflexos.dellemc.com", "east"]}]`,
+		},
+		"invalid query": {
+			body:           `{"annotation": {"query": "{\"color\": \"blue\"}"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			history := mocks.NewMockVolumeEventGetter(ctrl)
+			history.EXPECT().GetVolumeEvents(tc.since).AnyTimes().Return(testEvents())
+
+			ctx, teardown := setup(nil)
+			defer teardown()
+			ctx.svc.VolumeHistory = history
+
+			status, body := post(t, ctx.server.URL+"/events/annotations", tc.body)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, tc.expected, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Response formats of the topology endpoints
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// formatParam is the query string parameter that selects the response format, taking precedence over the Accept header
+const formatParam = "format"
+
+// ndjsonFlushRows is the number of NDJSON lines written between flushes of the response
+const ndjsonFlushRows = 100
+
+// contentTypes maps the response formats to their content types
+var contentTypes = map[string]string{
+	formatJSON:   "application/json; charset=UTF-8",
+	formatCSV:    "text/csv; charset=UTF-8",
+	formatNDJSON: "application/x-ndjson",
+}
+
+// responseFormat negotiates the response format from the format parameter or the Accept header, defaulting to JSON
+func responseFormat(r *http.Request) (string, error) {
+	if format := strings.ToLower(r.URL.Query().Get(formatParam)); format != "" {
+		if _, ok := contentTypes[format]; !ok {
+			return "", fmt.Errorf("unsupported format %q; valid formats are %s, %s and %s", format, formatJSON, formatCSV, formatNDJSON)
+		}
+		return format, nil
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			return formatCSV, nil
+		case "application/x-ndjson", "application/ndjson":
+			return formatNDJSON, nil
+		case "application/json":
+			return formatJSON, nil
+		}
+	}
+	return formatJSON, nil
+}
+
+// appendUnique appends the rows of table that are not already in rows
+func appendUnique(rows [][]string, table [][]string) [][]string {
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		seen[strings.Join(row, "\x00")] = true
+	}
+	for _, row := range table {
+		if key := strings.Join(row, "\x00"); !seen[key] {
+			seen[key] = true
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// writeTableExport writes the rows of a table as CSV, with the column titles as header, or as one JSON object per line
+func (s *Service) writeTableExport(w http.ResponseWriter, format string, table topologyTable, rows [][]string) {
+	switch format {
+	case formatCSV:
+		s.writeCSV(w, table.file, table.schema.titles(), rows)
+	case formatNDJSON:
+		objects := make([]interface{}, 0, len(rows))
+		for _, object := range table.schema.objects(rows) {
+			objects = append(objects, object)
+		}
+		s.writeNDJSON(w, objects)
+	}
+}
+
+// writeListExport writes a page of a REST API list as CSV, with the fields of its rows, or as NDJSON, with its items;
+// the total and continue token are sent as headers
+func writeListExport[T any](s *Service, w http.ResponseWriter, format string, table topologyTable, fields []int, rows [][]string, page listPage, items []T) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(page.total))
+	if page.next != "" {
+		w.Header().Set("X-Continue", page.next)
+	}
+
+	switch format {
+	case formatCSV:
+		records := make([][]string, 0, len(page.indexes))
+		for _, index := range page.indexes {
+			record := make([]string, 0, len(fields))
+			for _, field := range fields {
+				record = append(record, csvValue(jsonValue(table.schema[field].Type, rows[index][field])))
+			}
+			records = append(records, record)
+		}
+		s.writeCSV(w, table.name, table.schema.fieldTitles(fields), records)
+	case formatNDJSON:
+		objects := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			objects = append(objects, item)
+		}
+		s.writeNDJSON(w, objects)
+	}
+}
+
+// writeCSV writes the header and records as a CSV attachment named after name
+func (s *Service) writeCSV(w http.ResponseWriter, name string, header []string, records [][]string) {
+	w.Header().Set("Content-Type", contentTypes[formatCSV])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		s.Logger.WithError(err).Error("writing csv response")
+		return
+	}
+	if err := writer.WriteAll(records); err != nil {
+		s.Logger.WithError(err).Error("writing csv response")
+	}
+}
+
+// writeNDJSON streams the objects as newline-delimited JSON, flushing the response as it goes
+func (s *Service) writeNDJSON(w http.ResponseWriter, objects []interface{}) {
+	w.Header().Set("Content-Type", contentTypes[formatNDJSON])
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for i, object := range objects {
+		if err := encoder.Encode(object); err != nil {
+			s.Logger.WithError(err).Error("writing ndjson response")
+			return
+		}
+		if flusher != nil && (i+1)%ndjsonFlushRows == 0 {
+			flusher.Flush()
+		}
+	}
+}
+
+// csvValue formats a REST API value as a CSV cell; missing values are empty
+func csvValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
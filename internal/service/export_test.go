@@ -0,0 +1,169 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func request(t *testing.T, method, url, accept, body string) (*http.Response, []byte) {
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	assert.Nil(t, err)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	assert.Nil(t, err)
+	return res, resBody
+}
+
+func TestQueryExport(t *testing.T) {
+	tests := map[string]struct {
+		path                string
+		accept              string
+		body                string
+		expectedStatus      int
+		expectedContentType string
+		expectedVolumes     []string
+	}{
+		"csv by parameter": {
+			path:                "/query?format=csv",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedVolumes:     []string{"pv-1", "pv-2"},
+		},
+		"csv by accept header": {
+			path:                "/topology.json",
+			accept:              "text/csv",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedVolumes:     []string{"pv-1", "pv-2"},
+		},
+		"ndjson by accept header": {
+			path:                "/query",
+			accept:              "application/x-ndjson",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedVolumes:     []string{"pv-1", "pv-2"},
+		},
+		"parameter overrides accept header": {
+			path:                "/query?format=ndjson",
+			accept:              "text/csv",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedVolumes:     []string{"pv-1", "pv-2"},
+		},
+		"filtered targets exported once": {
+			path: "/query?format=csv",
+			body: `{"targets": [
+				{"target": "{\"Namespace\": \"ns-2\"}", "refId": "A"},
+				{"target": "{}", "refId": "B"},
+				{"target": "{\"Namespace\": \"ns-1\"}", "refId": "C", "hide": true}
+			]}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedVolumes:     []string{"pv-2", "pv-1"},
+		},
+		"ad-hoc filters": {
+			path:                "/query?format=ndjson",
+			body:                `{"adhocFilters": [{"key": "Namespace", "operator": "=", "value": "ns-1"}]}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedVolumes:     []string{"pv-1"},
+		},
+		"aggregation target": {
+			path:           "/query?format=csv",
+			body:           `{"targets": [{"target": "{\"$groupBy\": \"Namespace\"}", "refId": "A"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unsupported format": {
+			path:           "/query?format=xml",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(testVolumes(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			res, body := request(t, http.MethodPost, ctx.server.URL+tc.path, tc.accept, tc.body)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tc.expectedContentType, res.Header.Get("Content-Type"))
+
+			volumes := make([]string, 0)
+			if strings.HasPrefix(tc.expectedContentType, "text/csv") {
+				assert.Equal(t, `attachment; filename="topology.csv"`, res.Header.Get("Content-Disposition"))
+				records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+				assert.Nil(t, err)
+				assert.Equal(t, "Persistent Volume", records[0][1])
+				for _, record := range records[1:] {
+					volumes = append(volumes, record[1])
+				}
+			} else {
+				for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+					var row map[string]string
+					assert.Nil(t, json.Unmarshal([]byte(line), &row))
+					volumes = append(volumes, row["persistent_volume"])
+				}
+			}
+			assert.Equal(t, tc.expectedVolumes, volumes)
+		})
+	}
+}
+
+func TestListVolumesExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(2).Return(testVolumes(), nil)
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	res, body := request(t, http.MethodGet, ctx.server.URL+"/api/v1/volumes?fields=persistent_volume,provisioned_size,created&limit=1", "text/csv", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "2", res.Header.Get("X-Total-Count"))
+	assert.NotEmpty(t, res.Header.Get("X-Continue"))
+	assert.Equal(t, `attachment; filename="volumes.csv"`, res.Header.Get("Content-Disposition"))
+	assert.Equal(t, "Persistent Volume,Provisioned Size,Created\npv-1,8589934592,2020-07-28T20:00:00Z\n", string(body))
+
+	res, body = request(t, http.MethodGet, ctx.server.URL+"/api/v1/volumes?format=ndjson&fields=persistent_volume&sort=-persistent_volume", "", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "2", res.Header.Get("X-Total-Count"))
+	assert.Empty(t, res.Header.Get("X-Continue"))
+	assert.Equal(t, "{\"persistent_volume\":\"pv-2\"}\n{\"persistent_volume\":\"pv-1\"}\n", string(body))
+}
@@ -0,0 +1,463 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter grammar
+//
+// A target filter is a JSON object whose keys are column names (the column title such as "Storage Pool"
+// or its JSON name such as "storage_pool", case-insensitive) and whose values select the matching rows.
+// All keys of an object must match for a row to be selected; unknown column names are rejected.
+//
+//	"value"           the column equals value
+//	"{a,b}"           the column equals one of the values, as Grafana formats multi-value variables
+//	"=value"          the column equals value; use this form when value itself starts with an operator or a brace
+//	"!=value"         the column does not equal value
+//	"=~regex"         the whole column matches the regular expression
+//	"!~regex"         the whole column does not match the regular expression
+//	">value"          the column is greater than value, also ">=", "<" and "<="
+//	["a", "b"]        the column equals one of the values
+//	"$or": [{}, {}]   at least one of the nested filters matches
+//
+// The reserved top-level key "$inTimeRange": true additionally restricts the target to rows created inside the
+// time range of the dashboard.
+//
+// The reserved top-level keys "$groupBy" and "$aggregate" turn the target into an aggregation, see aggregation.
+//
+// Number columns such as "Provisioned Size" compare quantities, so "16Gi" equals "17179869184" and ">=10Gi" is
+// a valid filter. Time columns such as "Created" compare instants written as RFC 3339 ("2020-07-28T20:00:00Z"),
+// dates ("2020-07-28"), Unix milliseconds or relative to the current time ("now", "now-7d", "now-12h").
+//
+// For example {"Namespace": ["ns-1", "ns-2"], "Status": "!=Released", "Provisioned Size": ">=100Gi", "Created": ">now-7d"}
+
+// Filter operators
+const (
+	// opImplicit is the operator of expressions without an operator: equality, or membership for multi-value variables
+	opImplicit  = ""
+	opEqual     = "="
+	opNotEqual  = "!="
+	opRegex     = "=~"
+	opNotRegex  = "!~"
+	opGreater   = ">"
+	opGreaterEq = ">="
+	opLess      = "<"
+	opLessEq    = "<="
+	orFilterKey = "$or"
+	// inTimeRangeKey is the reserved top-level key restricting a target to rows created inside the dashboard time range
+	inTimeRangeKey = "$inTimeRange"
+)
+
+// operators lists the filter operators, longest first so that prefixes are matched correctly
+var operators = []string{opNotEqual, opRegex, opNotRegex, opGreaterEq, opLessEq, opGreater, opLess, opEqual}
+
+// rowFilter selects rows of a topology table
+type rowFilter interface {
+	match(row []string) bool
+}
+
+// allFilter matches rows that match all of its filters
+type allFilter []rowFilter
+
+func (f allFilter) match(row []string) bool {
+	for _, filter := range f {
+		if !filter.match(row) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyFilter matches rows that match at least one of its filters
+type anyFilter []rowFilter
+
+func (f anyFilter) match(row []string) bool {
+	for _, filter := range f {
+		if filter.match(row) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnFilter matches rows whose column value satisfies the predicate
+type columnFilter struct {
+	index     int
+	predicate func(string) bool
+}
+
+func (f columnFilter) match(row []string) bool {
+	return f.predicate(row[f.index])
+}
+
+// targetQuery is a parsed Grafana target: the row filter plus the options set by reserved top-level keys
+type targetQuery struct {
+	filter rowFilter
+	// inTimeRange restricts the rows to those created inside the dashboard time range
+	inTimeRange bool
+	// aggregation summarizes the rows instead of returning them; nil for plain targets
+	aggregation *aggregation
+}
+
+// parseTarget parses a Grafana target; an empty target matches every row
+func (schema tableSchema) parseTarget(target string) (targetQuery, error) {
+	if strings.TrimSpace(target) == "" {
+		return targetQuery{filter: allFilter{}}, nil
+	}
+	fields, err := decodeTarget(target)
+	if err != nil {
+		return targetQuery{}, err
+	}
+	return schema.parseTargetFields(fields)
+}
+
+// decodeTarget decodes the top-level keys of a Grafana target.
+// Targets typed with escaped quotes such as {\"Namespace\":\"ns-1\"} are accepted as well.
+func decodeTarget(target string) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	err := UnMarshalFn([]byte(target), &fields)
+	if err != nil && strings.Contains(target, "\\") {
+		err = UnMarshalFn([]byte(strings.ReplaceAll(target, "\\", "")), &fields)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %s: %v", target, err)
+	}
+	return fields, nil
+}
+
+// parseTargetFields parses the decoded keys of a Grafana target: the reserved keys and the column filters
+func (schema tableSchema) parseTargetFields(fields map[string]json.RawMessage) (targetQuery, error) {
+	var err error
+	var query targetQuery
+	if data, ok := fields[inTimeRangeKey]; ok {
+		if err := json.Unmarshal(data, &query.inTimeRange); err != nil {
+			return targetQuery{}, fmt.Errorf("%q must be true or false", inTimeRangeKey)
+		}
+		delete(fields, inTimeRangeKey)
+	}
+
+	if query.aggregation, err = schema.parseAggregation(fields); err != nil {
+		return targetQuery{}, err
+	}
+
+	query.filter, err = schema.parseFields(fields)
+	return query, err
+}
+
+// rowFilter returns the filter selecting the target's rows for a query over the time range
+func (schema tableSchema) rowFilter(query targetQuery, tr timeRange) rowFilter {
+	if !query.inTimeRange {
+		return query.filter
+	}
+	for i, column := range schema {
+		if column.Type == columnTime {
+			return allFilter{query.filter, columnFilter{index: i, predicate: func(v string) bool {
+				t, ok := parseTime(v)
+				return ok && tr.contains(t)
+			}}}
+		}
+	}
+	return query.filter
+}
+
+// parseFilter parses a filter written in the filter grammar
+func (schema tableSchema) parseFilter(data []byte) (rowFilter, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := UnMarshalFn(data, &fields); err != nil {
+		return nil, fmt.Errorf("invalid filter %s: %v", string(data), err)
+	}
+	return schema.parseFields(fields)
+}
+
+// parseFields parses the keys of a filter object into a filter matching all of them
+func (schema tableSchema) parseFields(fields map[string]json.RawMessage) (rowFilter, error) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filters := make(allFilter, 0, len(keys))
+	for _, key := range keys {
+		if key == orFilterKey {
+			filter, err := schema.parseOrFilter(fields[key])
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, filter)
+			continue
+		}
+
+		index, err := schema.column(key)
+		if err != nil {
+			return nil, err
+		}
+		predicate, err := parseValue(schema[index], fields[key])
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, columnFilter{index: index, predicate: predicate})
+	}
+	return filters, nil
+}
+
+// parseOrFilter parses the list of nested filters of an "$or" key
+func (schema tableSchema) parseOrFilter(data json.RawMessage) (rowFilter, error) {
+	var nested []json.RawMessage
+	if err := json.Unmarshal(data, &nested); err != nil {
+		return nil, fmt.Errorf("%q must be a list of filters", orFilterKey)
+	}
+
+	filters := make(anyFilter, 0, len(nested))
+	for _, n := range nested {
+		filter, err := schema.parseFilter(n)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// parseValue parses the value of a column filter into a predicate
+func parseValue(column tableColumn, data json.RawMessage) (func(string) bool, error) {
+	var expression string
+	if err := json.Unmarshal(data, &expression); err == nil {
+		operator, operand := splitOperator(expression)
+		if operator != opImplicit {
+			return newPredicate(column, operator, operand)
+		}
+		if values, ok := multiValue(operand); ok {
+			return equalAny(column, values)
+		}
+		return newPredicate(column, opEqual, operand)
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err == nil {
+		return equalAny(column, values)
+	}
+
+	return nil, fmt.Errorf("filter for %q must be a string or a list of strings, got %s", column.Text, string(data))
+}
+
+// equalAny returns a predicate matching column values equal to one of the values
+func equalAny(column tableColumn, values []string) (func(string) bool, error) {
+	predicates := make([]func(string) bool, 0, len(values))
+	for _, value := range values {
+		predicate, err := newPredicate(column, opEqual, value)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+	return func(v string) bool {
+		for _, predicate := range predicates {
+			if predicate(v) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// multiValue returns the values of a Grafana multi-value variable formatted as "{a,b}"
+func multiValue(expression string) ([]string, bool) {
+	if len(expression) < 2 || !strings.HasPrefix(expression, "{") || !strings.HasSuffix(expression, "}") {
+		return nil, false
+	}
+	return strings.Split(expression[1:len(expression)-1], ","), true
+}
+
+// splitOperator splits a filter expression into its operator and operand; expressions without an operator return
+// opImplicit
+func splitOperator(expression string) (string, string) {
+	for _, operator := range operators {
+		if strings.HasPrefix(expression, operator) {
+			return operator, strings.TrimPrefix(expression, operator)
+		}
+	}
+	return opImplicit, expression
+}
+
+// newPredicate returns a predicate comparing a column value to the operand using the operator
+func newPredicate(column tableColumn, operator, operand string) (func(string) bool, error) {
+	switch operator {
+	case opRegex, opNotRegex:
+		re, err := regexp.Compile("^(?:" + operand + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression for %q: %v", column.Text, err)
+		}
+		negate := operator == opNotRegex
+		return func(v string) bool { return re.MatchString(v) != negate }, nil
+	case opEqual, opNotEqual, opGreater, opGreaterEq, opLess, opLessEq:
+	default:
+		return nil, fmt.Errorf("unsupported operator %q for %q", operator, column.Text)
+	}
+
+	var compare func(string) (int, bool)
+	switch column.Type {
+	case columnNumber:
+		want, ok := parseBytes(operand)
+		if !ok {
+			return nil, fmt.Errorf("invalid quantity %q for %q", operand, column.Text)
+		}
+		compare = func(v string) (int, bool) {
+			got, ok := parseBytes(v)
+			return cmpInt64(got, want), ok
+		}
+	case columnTime:
+		want, err := parseTimeOperand(operand, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q for %q: %v", operand, column.Text, err)
+		}
+		compare = func(v string) (int, bool) {
+			got, ok := parseTime(v)
+			return got.Compare(want), ok
+		}
+	default:
+		if operator != opEqual && operator != opNotEqual {
+			return nil, fmt.Errorf("operator %q is only supported for number and time columns, not %q", operator, column.Text)
+		}
+		compare = func(v string) (int, bool) {
+			return strings.Compare(v, operand), true
+		}
+	}
+
+	return func(v string) bool {
+		c, ok := compare(v)
+		if !ok {
+			return operator == opNotEqual
+		}
+		switch operator {
+		case opEqual:
+			return c == 0
+		case opNotEqual:
+			return c != 0
+		case opGreater:
+			return c > 0
+		case opGreaterEq:
+			return c >= 0
+		case opLess:
+			return c < 0
+		default:
+			return c <= 0
+		}
+	}, nil
+}
+
+// parseTimeOperand parses the operand of a time filter
+func parseTimeOperand(operand string, now time.Time) (time.Time, error) {
+	operand = strings.TrimSpace(operand)
+	if strings.HasPrefix(operand, "now") {
+		offset := strings.TrimPrefix(operand, "now")
+		if offset == "" {
+			return now, nil
+		}
+		d, err := parseDuration(offset)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, operand); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, operand); err == nil {
+		return t, nil
+	}
+	if ms, err := strconv.ParseInt(operand, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Time{}, errors.New("expected RFC 3339, a date, Unix milliseconds or now[+-]duration")
+}
+
+// parseDuration parses a signed Go duration, also accepting days ("7d") and weeks ("2w")
+func parseDuration(s string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if strings.HasSuffix(s, suffix) {
+			n, err := strconv.ParseInt(strings.TrimSuffix(s, suffix), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
+
+// cmpInt64 compares two integers, returning -1, 0 or +1
+func cmpInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// column returns the index of the column with the given title or JSON name
+func (schema tableSchema) column(name string) (int, error) {
+	for i, column := range schema {
+		if strings.EqualFold(name, column.Text) || strings.EqualFold(name, column.Key) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown filter column %q; valid columns are %s", name, strings.Join(schema.titles(), ", "))
+}
+
+// titles returns the titles of the columns in schema order
+func (schema tableSchema) titles() []string {
+	titles := make([]string, 0, len(schema))
+	for _, column := range schema {
+		titles = append(titles, column.Text)
+	}
+	return titles
+}
+
+// adhocRowFilter converts Grafana ad-hoc filters into a filter matching all of them
+func (schema tableSchema) adhocRowFilter(adhocFilters []adhocFilter) (rowFilter, error) {
+	filters := make(allFilter, 0, len(adhocFilters))
+	for _, adhoc := range adhocFilters {
+		index, err := schema.column(adhoc.Key)
+		if err != nil {
+			return nil, err
+		}
+		operator := adhoc.Operator
+		if operator == "" {
+			operator = opEqual
+		}
+		predicate, err := newPredicate(schema[index], operator, adhoc.Value)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, columnFilter{index: index, predicate: predicate})
+	}
+	return filters, nil
+}
@@ -0,0 +1,313 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetFilters(t *testing.T) {
+	tests := map[string]struct {
+		filter             string
+		expectedStatus     int
+		expectedVolumes    []string
+		expectedMessageSub string
+	}{
+		"empty target": {
+			filter:          ``,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1", "pv-2"},
+		},
+		"equality": {
+			filter:          `{"Namespace": "ns-1"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"equality is exact": {
+			filter:          `{"Namespace": "ns"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{},
+		},
+		"value is not a pattern": {
+			filter:          `{"Status": "(Bound|Released)"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{},
+		},
+		"grafana multi-value variable": {
+			filter:          `{"Namespace": "{ns-1,ns-3}"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"multi-value variable values are exact": {
+			filter:          `{"Namespace": "{ns,ns-}"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{},
+		},
+		"equality with a brace": {
+			filter:          `{"Namespace": "={ns-1,ns-3}"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{},
+		},
+		"empty column only equals an empty value": {
+			filter:          `{"Attach Error": "host not found on array"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"json column name": {
+			filter:          `{"csi_driver": "csi-vxflexos.dellemc.com"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"workload": {
+			filter:          `{"Owner Kind": "StatefulSet", "owner": "web"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"node of one of several pods": {
+			filter:          `{"Node": "=~.*\\bnode-2\\b.*"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"attached node": {
+			filter:          `{"Attached Node": "node-2"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"failed attachments": {
+			filter:          `{"Attach Error": "!="}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"volumes without pods": {
+			filter:          `{"Pod": ""}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"explicit equality": {
+			filter:          `{"Status": "=Bound"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"negation": {
+			filter:          `{"Status": "!=Released"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"regex": {
+			filter:          `{"Status": "=~(Bound|Pending)"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"regex matches the whole value": {
+			filter:          `{"Storage Pool": "=~pool"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{},
+		},
+		"negated regex": {
+			filter:          `{"Storage Pool": "!~N/.*"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"set membership": {
+			filter:          `{"Namespace": ["ns-1", "ns-2", "ns-3"]}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1", "pv-2"},
+		},
+		"or across columns": {
+			filter:          `{"$or": [{"Namespace": "ns-1"}, {"Storage Pool": "pool-1"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1", "pv-2"},
+		},
+		"and with or": {
+			filter:          `{"Protocol": "scsi", "$or": [{"Namespace": "ns-3"}, {"Status": "Released"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"escaped quotes": {
+			filter:          `{\"Namespace\": \"ns-2\"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"any column": {
+			filter:          `{"Persistent Volume Claim": "pvc-2", "storage_system_volume_name": "k8s-pv-2"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"size equality compares quantities": {
+			filter:          `{"Provisioned Size": "8589934592"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"size range": {
+			filter:          `{"Provisioned Size": ">=10Gi"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"size set membership": {
+			filter:          `{"provisioned_size": ["8Gi", "32Gi"]}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"created before date": {
+			filter:          `{"Created": "<2020-08-01"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"created window": {
+			filter:          `{"$or": [{"Created": ">2020-08-28T19:00:00Z"}, {"Created": "<=1595966400000"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1", "pv-2"},
+		},
+		"created relative to now": {
+			filter:          `{"Created": ">now-7d"}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{},
+		},
+		"unknown column": {
+			filter:             `{"Color": "blue"}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedMessageSub: "unknown filter column \"Color\"",
+		},
+		"comparison on string column": {
+			filter:             `{"Namespace": ">ns-1"}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedMessageSub: "only supported for number and time columns",
+		},
+		"invalid quantity": {
+			filter:             `{"Provisioned Size": ">lots"}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedMessageSub: "invalid quantity",
+		},
+		"invalid time": {
+			filter:             `{"Created": ">yesterday"}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedMessageSub: "invalid time",
+		},
+		"invalid relative time": {
+			filter:             `{"Created": ">now-xd"}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedMessageSub: "invalid duration",
+		},
+		"invalid json": {
+			filter:             `{"Namespace": `,
+			expectedStatus:     http.StatusBadRequest,
+			expectedMessageSub: "invalid filter",
+		},
+		"invalid regex": {
+			filter:             `{"Namespace": "=~("}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedMessageSub: "invalid regular expression",
+		},
+		"invalid value type": {
+			filter:             `{"Namespace": 1}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedMessageSub: "must be a string or a list of strings",
+		},
+		"invalid or": {
+			filter:             `{"$or": {"Namespace": "ns-1"}}`,
+			expectedStatus:     http.StatusBadRequest,
+			expectedMessageSub: "must be a list of filters",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(testVolumes(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			target, err := json.Marshal(tc.filter)
+			assert.Nil(t, err)
+			status, body := post(t, ctx.server.URL+"/query", `{"targets": [{"refId": "A", "type": "table", "target": `+string(target)+`}]}`)
+			assert.Equal(t, tc.expectedStatus, status)
+
+			if tc.expectedStatus != http.StatusOK {
+				var message struct {
+					Message string `json:"message"`
+				}
+				assert.Nil(t, json.Unmarshal(body, &message))
+				assert.Contains(t, message.Message, tc.expectedMessageSub)
+				return
+			}
+
+			var frames []testFrame
+			assert.Nil(t, json.Unmarshal(body, &frames))
+			volumes := make([]string, 0)
+			for _, row := range frames[0].Rows {
+				volumes = append(volumes, row[1].(string))
+			}
+			assert.Equal(t, tc.expectedVolumes, volumes)
+		})
+	}
+}
+
+func TestTargetTimeRange(t *testing.T) {
+	tests := map[string]struct {
+		body            string
+		expectedStatus  int
+		expectedVolumes []string
+	}{
+		"opted in": {
+			body:            `{"range": {"from": "2020-08-01T00:00:00Z", "to": "2020-09-01T00:00:00Z"}, "targets": [{"refId": "A", "type": "table", "target": "{\"$inTimeRange\": true}"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-2"},
+		},
+		"opted in with filter": {
+			body:            `{"range": {"from": "2020-07-01T00:00:00Z", "to": "2020-09-01T00:00:00Z"}, "targets": [{"refId": "A", "type": "table", "target": "{\"$inTimeRange\": true, \"Namespace\": \"ns-1\"}"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1"},
+		},
+		"not opted in": {
+			body:            `{"range": {"from": "2020-08-01T00:00:00Z", "to": "2020-09-01T00:00:00Z"}, "targets": [{"refId": "A", "type": "table", "target": "{\"$inTimeRange\": false}"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1", "pv-2"},
+		},
+		"no range": {
+			body:            `{"targets": [{"refId": "A", "type": "table", "target": "{\"$inTimeRange\": true}"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedVolumes: []string{"pv-1", "pv-2"},
+		},
+		"invalid option": {
+			body:           `{"targets": [{"refId": "A", "type": "table", "target": "{\"$inTimeRange\": \"yes\"}"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, frames := queryFrames(t, tc.body)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			volumes := make([]string, 0)
+			for _, row := range frames[0].Rows {
+				volumes = append(volumes, row[1].(string))
+			}
+			assert.Equal(t, tc.expectedVolumes, volumes)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	tracer "github.com/dell/karavi-topology/internal/tracers"
+)
+
+// createdTimeLayout is the layout of k8s.VolumeInfo.CreatedTime
+const createdTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// timeRange is the dashboard time range sent by Grafana
+type timeRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// adhocFilter is a Grafana ad-hoc filter applied to every target of a query
+type adhocFilter struct {
+	Key      string `json:"key"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// tagKey is an entry of the /tag-keys response
+type tagKey struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// tagValue is an entry of the /tag-values response
+type tagValue struct {
+	Text string `json:"text"`
+}
+
+// annotation is an entry of the /annotations response
+type annotation struct {
+	Annotation interface{} `json:"annotation,omitempty"`
+	Time       int64       `json:"time"`
+	Title      string      `json:"title"`
+	Text       string      `json:"text"`
+	Tags       []string    `json:"tags"`
+}
+
+// searchRequest returns the names of the volume columns that can be used in target filters
+func (s *Service) searchRequest(w http.ResponseWriter, r *http.Request) {
+	s.search(w, r, volumeSchema)
+}
+
+// tagKeysRequest returns the volume columns that can be used as Grafana ad-hoc filter keys
+func (s *Service) tagKeysRequest(w http.ResponseWriter, _ *http.Request) {
+	s.tagKeys(w, volumeSchema)
+}
+
+// tagValuesRequest returns the distinct values currently present for the requested volume column
+func (s *Service) tagValuesRequest(w http.ResponseWriter, r *http.Request) {
+	s.tagValues(w, r, s.volumeTable())
+}
+
+// search returns the names of the columns of schema that can be used in target filters
+func (s *Service) search(w http.ResponseWriter, r *http.Request, schema tableSchema) {
+	var requestBody struct {
+		Target string `json:"target"`
+	}
+	if err := DecodeBodyFn(r.Body, &requestBody); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		s.Logger.WithError(err).Error("decoding search body")
+		return
+	}
+
+	names := make([]string, 0)
+	for _, name := range schema.titles() {
+		if strings.Contains(strings.ToLower(name), strings.ToLower(requestBody.Target)) {
+			names = append(names, name)
+		}
+	}
+	s.writeJSON(w, names)
+}
+
+// tagKeys returns the columns of schema that can be used as Grafana ad-hoc filter keys
+func (s *Service) tagKeys(w http.ResponseWriter, schema tableSchema) {
+	keys := make([]tagKey, 0, len(schema))
+	for _, column := range schema {
+		keyType := columnString
+		if column.Type == columnNumber {
+			keyType = columnNumber
+		}
+		keys = append(keys, tagKey{Type: keyType, Text: column.Text})
+	}
+	s.writeJSON(w, keys)
+}
+
+// tagValues returns the distinct values currently present for the requested column of a table
+func (s *Service) tagValues(w http.ResponseWriter, r *http.Request, table topologyTable) {
+	ctx, span := tracer.GetTracer(context.Background(), "tagValuesRequest")
+	defer span.End()
+
+	var requestBody struct {
+		Key string `json:"key"`
+	}
+	if err := DecodeBodyFn(r.Body, &requestBody); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		s.Logger.WithError(err).Error("decoding tag values body")
+		return
+	}
+
+	rows, err := table.rows(ctx)
+	if err != nil {
+		w.WriteHeader(errorStatus(err))
+		s.Logger.WithError(err).Errorf("getting %s", table.name)
+		return
+	}
+
+	distinct := make(map[string]struct{})
+	if index, err := table.schema.column(requestBody.Key); err == nil {
+		for _, row := range rows {
+			if value := row[index]; value != "" {
+				distinct[value] = struct{}{}
+			}
+		}
+	}
+
+	values := make([]tagValue, 0, len(distinct))
+	for value := range distinct {
+		values = append(values, tagValue{Text: value})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Text < values[j].Text })
+	s.writeJSON(w, values)
+}
+
+// annotationsRequest returns a volume creation event for every volume created inside the dashboard time range;
+// unbound claims have no volume and are left out
+func (s *Service) annotationsRequest(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.GetTracer(context.Background(), "annotationsRequest")
+	defer span.End()
+
+	var requestBody struct {
+		Range      timeRange `json:"range"`
+		Annotation struct {
+			Name  string `json:"name"`
+			Query string `json:"query"`
+		} `json:"annotation"`
+	}
+	if err := DecodeBodyFn(r.Body, &requestBody); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		s.Logger.WithError(err).Error("decoding annotations body")
+		return
+	}
+
+	query, err := volumeSchema.parseTarget(requestBody.Annotation.Query)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("annotation query: %v", err))
+		s.Logger.WithError(err).Errorf("parsing annotation query: %s", requestBody.Annotation.Query)
+		return
+	}
+
+	volumes, err := s.VolumeFinder.GetPersistentVolumes(ctx)
+	if err != nil {
+		w.WriteHeader(errorStatus(err))
+		s.Logger.WithError(err).Error("getting persistent volumes")
+		return
+	}
+
+	annotations := make([]annotation, 0)
+	for _, volume := range generateVolumeTableJSON(volumes, query.filter) {
+		if volume.PersistentVolume == "" {
+			continue
+		}
+		created, ok := parseTime(volume.Created)
+		if !ok {
+			s.Logger.WithField("persistent_volume", volume.PersistentVolume).Debug("parsing volume creation time")
+			continue
+		}
+		if !requestBody.Range.contains(created) {
+			continue
+		}
+		annotations = append(annotations, annotation{
+			Annotation: requestBody.Annotation,
+			Time:       created.UnixMilli(),
+			Title:      fmt.Sprintf("Volume %s created", volume.PersistentVolume),
+			Text: fmt.Sprintf("%s/%s provisioned %s on %s by %s",
+				volume.Namespace, volume.PersistentVolumeClaim, volume.ProvisionedSize, volume.StorageSystem, volume.CSIDriver),
+			Tags: []string{volume.Namespace, volume.CSIDriver, volume.StorageSystem},
+		})
+	}
+	sort.Slice(annotations, func(i, j int) bool { return annotations[i].Time < annotations[j].Time })
+	s.writeJSON(w, annotations)
+}
+
+// contains returns true if t lies inside the range; a zero bound is treated as open
+func (tr timeRange) contains(t time.Time) bool {
+	if !tr.From.IsZero() && t.Before(tr.From) {
+		return false
+	}
+	if !tr.To.IsZero() && t.After(tr.To) {
+		return false
+	}
+	return true
+}
+
+// writeError writes err as a JSON message that Grafana displays to the user
+func (s *Service) writeError(w http.ResponseWriter, status int, err error) {
+	output, mErr := MarshalFn(map[string]string{"message": err.Error()})
+	if mErr != nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(output)
+}
+
+// writeJSON marshals v and writes it as the JSON response
+func (s *Service) writeJSON(w http.ResponseWriter, v interface{}) {
+	output, err := MarshalFn(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		s.Logger.WithError(err).Error("marshalling response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	if _, err = HTTPWrite(&w, output); err != nil {
+		s.Logger.WithError(err).Error("writing response")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,321 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testVolumes() []k8s.VolumeInfo {
+	t1, _ := time.Parse(time.RFC3339, "2020-07-28T20:00:00Z")
+	t2, _ := time.Parse(time.RFC3339, "2020-08-28T20:00:00Z")
+	return []k8s.VolumeInfo{
+		{
+			Namespace:               "ns-1",
+			VolumeClaimName:         "pvc-1",
+			PersistentVolume:        "pv-1",
+			PersistentVolumeStatus:  "Bound",
+			StorageClass:            "powerstore",
+			Driver:                  "csi-powerstore.dellemc.com",
+			ProvisionedSize:         "8Gi",
+			StorageSystemVolumeName: "pv-1",
+			StoragePoolName:         "N/A",
+			StorageSystem:           "10.0.0.1",
+			Protocol:                "scsi",
+			CreatedTime:             t1.String(),
+			Pods: []k8s.PodInfo{
+				{Name: "web-0", Node: "node-1", OwnerKind: "StatefulSet", OwnerName: "web"},
+				{Name: "web-1", Node: "node-2", OwnerKind: "StatefulSet", OwnerName: "web"},
+			},
+			AttachedNode: "node-1",
+			AttachStatus: "Attached",
+		},
+		{
+			Namespace:               "ns-2",
+			VolumeClaimName:         "pvc-2",
+			PersistentVolume:        "pv-2",
+			PersistentVolumeStatus:  "Released",
+			StorageClass:            "vxflexos",
+			Driver:                  "csi-vxflexos.dellemc.com",
+			ProvisionedSize:         "16Gi",
+			StorageSystemVolumeName: "k8s-pv-2",
+			StoragePoolName:         "pool-1",
+			StorageSystem:           "7045c4cc20dffc0f",
+			Protocol:                "scsi",
+			CreatedTime:             t2.String(),
+			AttachedNode:            "node-2",
+			AttachStatus:            "Attaching",
+			AttachError:             "host not found on array",
+		},
+	}
+}
+
+var allColumns = []string{"Namespace", "Persistent Volume", "Status", "Persistent Volume Claim", "CSI Driver", "Created", "Provisioned Size", "Storage Class", "Storage System Volume Name", "Storage Pool", "Storage System", "Protocol", "Pod", "Owner Kind", "Owner", "Node", "Attached Node", "Attach Status", "Attach Error", "Age", "Message", "Cluster"}
+
+func post(t *testing.T, url string, body string) (int, []byte) {
+	res, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	assert.Nil(t, err)
+	return res.StatusCode, resBody
+}
+
+func TestSearchHandler(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected []string
+	}{
+		"all columns": {
+			body:     `{"target": ""}`,
+			expected: allColumns,
+		},
+		"matching columns": {
+			body:     `{"target": "storage"}`,
+			expected: []string{"Storage Class", "Storage System Volume Name", "Storage Pool", "Storage System"},
+		},
+		"no body": {
+			body:     "",
+			expected: allColumns,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, teardown := setup(nil)
+			defer teardown()
+
+			status, body := post(t, ctx.server.URL+"/search", tc.body)
+			assert.Equal(t, http.StatusOK, status)
+
+			var result []string
+			assert.Nil(t, json.Unmarshal(body, &result))
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestTagKeysHandler(t *testing.T) {
+	ctx, teardown := setup(nil)
+	defer teardown()
+
+	status, body := post(t, ctx.server.URL+"/tag-keys", "{}")
+	assert.Equal(t, http.StatusOK, status)
+
+	var result []map[string]string
+	assert.Nil(t, json.Unmarshal(body, &result))
+	assert.Equal(t, len(allColumns), len(result))
+	assert.Equal(t, map[string]string{"type": "string", "text": "Namespace"}, result[0])
+	assert.Equal(t, map[string]string{"type": "number", "text": "Provisioned Size"}, result[6])
+}
+
+func TestTagValuesHandler(t *testing.T) {
+	tests := map[string]struct {
+		body           string
+		err            error
+		expectedStatus int
+		expected       []map[string]string
+	}{
+		"distinct namespaces": {
+			body:           `{"key": "Namespace"}`,
+			expectedStatus: http.StatusOK,
+			expected:       []map[string]string{{"text": "ns-1"}, {"text": "ns-2"}},
+		},
+		"distinct protocols": {
+			body:           `{"key": "Protocol"}`,
+			expectedStatus: http.StatusOK,
+			expected:       []map[string]string{{"text": "scsi"}},
+		},
+		"distinct volumes by json name": {
+			body:           `{"key": "persistent_volume"}`,
+			expectedStatus: http.StatusOK,
+			expected:       []map[string]string{{"text": "pv-1"}, {"text": "pv-2"}},
+		},
+		"unknown key": {
+			body:           `{"key": "unknown"}`,
+			expectedStatus: http.StatusOK,
+			expected:       []map[string]string{},
+		},
+		"error getting volumes": {
+			body:           `{"key": "Namespace"}`,
+			err:            errors.New("error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(testVolumes(), tc.err)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := post(t, ctx.server.URL+"/tag-values", tc.body)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expected != nil {
+				var result []map[string]string
+				assert.Nil(t, json.Unmarshal(body, &result))
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestAnnotationsHandler(t *testing.T) {
+	tests := map[string]struct {
+		body           string
+		expectedStatus int
+		expectedTitles []string
+	}{
+		"all volumes in range": {
+			body:           `{"range": {"from": "2020-07-01T00:00:00Z", "to": "2020-09-01T00:00:00Z"}, "annotation": {"name": "created"}}`,
+			expectedStatus: http.StatusOK,
+			expectedTitles: []string{"Volume pv-1 created", "Volume pv-2 created"},
+		},
+		"volumes outside range": {
+			body:           `{"range": {"from": "2020-08-01T00:00:00Z", "to": "2020-09-01T00:00:00Z"}, "annotation": {"name": "created"}}`,
+			expectedStatus: http.StatusOK,
+			expectedTitles: []string{"Volume pv-2 created"},
+		},
+		"unbound claims are left out": {
+			body:           `{"range": {"from": "2020-07-01T00:00:00Z", "to": "2020-09-01T00:00:00Z"}, "annotation": {"query": "{\"Status\": \"Unbound\"}"}}`,
+			expectedStatus: http.StatusOK,
+			expectedTitles: []string{},
+		},
+		"filtered by query": {
+			body:           `{"range": {"from": "2020-07-01T00:00:00Z", "to": "2020-09-01T00:00:00Z"}, "annotation": {"query": "{\"Namespace\": \"ns-1\"}"}}`,
+			expectedStatus: http.StatusOK,
+			expectedTitles: []string{"Volume pv-1 created"},
+		},
+		"invalid query": {
+			body:           `{"annotation": {"query": "not-json"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			unbound := k8s.VolumeInfo{
+				Namespace:              "ns-1",
+				VolumeClaimName:        "pvc-3",
+				PersistentVolumeStatus: k8s.PersistentVolumeStatusUnbound,
+				CreatedTime:            testVolumes()[1].CreatedTime,
+			}
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).AnyTimes().Return(append(testVolumes(), unbound), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := post(t, ctx.server.URL+"/annotations", tc.body)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var result []struct {
+				Time  int64    `json:"time"`
+				Title string   `json:"title"`
+				Tags  []string `json:"tags"`
+			}
+			assert.Nil(t, json.Unmarshal(body, &result))
+			titles := make([]string, 0)
+			for _, a := range result {
+				titles = append(titles, a.Title)
+			}
+			assert.Equal(t, tc.expectedTitles, titles)
+		})
+	}
+}
+
+func TestQueryAdhocFilters(t *testing.T) {
+	tests := map[string]struct {
+		body           string
+		expectedStatus int
+		expectedRows   int
+	}{
+		"equal": {
+			body:           `{"adhocFilters": [{"key": "Namespace", "operator": "=", "value": "ns-1"}]}`,
+			expectedStatus: http.StatusOK,
+			expectedRows:   1,
+		},
+		"not equal": {
+			body:           `{"adhocFilters": [{"key": "Status", "operator": "!=", "value": "Released"}]}`,
+			expectedStatus: http.StatusOK,
+			expectedRows:   1,
+		},
+		"regex": {
+			body:           `{"adhocFilters": [{"key": "CSI Driver", "operator": "=~", "value": "csi-.*"}]}`,
+			expectedStatus: http.StatusOK,
+			expectedRows:   2,
+		},
+		"negated regex": {
+			body:           `{"adhocFilters": [{"key": "Storage Pool", "operator": "!~", "value": "N/A"}]}`,
+			expectedStatus: http.StatusOK,
+			expectedRows:   1,
+		},
+		"invalid regex": {
+			body:           `{"adhocFilters": [{"key": "Namespace", "operator": "=~", "value": "("}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"numeric comparison": {
+			body:           `{"adhocFilters": [{"key": "Provisioned Size", "operator": ">", "value": "10Gi"}]}`,
+			expectedStatus: http.StatusOK,
+			expectedRows:   1,
+		},
+		"unsupported operator": {
+			body:           `{"adhocFilters": [{"key": "Namespace", "operator": ">", "value": "ns-1"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown key": {
+			body:           `{"adhocFilters": [{"key": "unknown", "operator": "=", "value": "ns-1"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(testVolumes(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := post(t, ctx.server.URL+"/query", tc.body)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var result []map[string]interface{}
+			assert.Nil(t, json.Unmarshal(body, &result))
+			assert.Equal(t, tc.expectedRows, len(result))
+		})
+	}
+}
@@ -0,0 +1,438 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	tracer "github.com/dell/karavi-topology/internal/tracers"
+	"github.com/sirupsen/logrus"
+)
+
+// Graph output formats
+const (
+	graphJSON    = "json"
+	graphDOT     = "dot"
+	graphMermaid = "mermaid"
+)
+
+// graphContentTypes maps the graph formats to their content types
+var graphContentTypes = map[string]string{
+	graphJSON:    "application/json; charset=UTF-8",
+	graphDOT:     "text/vnd.graphviz; charset=UTF-8",
+	graphMermaid: "text/plain; charset=UTF-8",
+}
+
+// includeKey is the reserved top-level key of graph targets listing the optional node kinds to add to the graph
+const includeKey = "$include"
+
+// graphKinds are the kinds of graph nodes, named after the volume column holding their name, in display order
+var graphKinds = []string{
+	"namespace", "pod", "persistent_volume_claim", "persistent_volume", "storage_class", "csi_driver", "storage_pool",
+	"storage_system", "node",
+}
+
+// optionalGraphKinds are the node kinds that are only added to the graph when a target includes them
+var optionalGraphKinds = []string{"pod", "node"}
+
+// topologyGraph is the graph of the Kubernetes and storage objects of a set of volumes. Edges go from the consumer
+// to the provider: namespace and pod to claim, claim to volume, volume to storage class and pool, and so on.
+type topologyGraph struct {
+	nodes map[string]*graphNode
+	edges map[[2]string]struct{}
+	// volumes is the number of volumes added, used to count every volume once per node
+	volumes int
+}
+
+// graphNode is a node of the topology graph
+type graphNode struct {
+	id      string
+	kind    string
+	title   string
+	cluster string
+	// size is the provisioned size of persistent volume nodes
+	size string
+	// volumes is the number of volumes connected to the node
+	volumes int
+	last    int
+}
+
+// graphRequest renders the volumes selected by the targets as a graph: Grafana Node Graph frames, or the union of
+// the targets' graphs as DOT or Mermaid text
+func (s *Service) graphRequest(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.GetTracer(context.Background(), "graphRequest")
+	defer span.End()
+
+	format := strings.ToLower(r.URL.Query().Get(formatParam))
+	if format == "" {
+		format = graphJSON
+	}
+	if _, ok := graphContentTypes[format]; !ok {
+		err := fmt.Errorf("unsupported format %q; valid formats are %s, %s and %s", format, graphJSON, graphDOT, graphMermaid)
+		s.writeError(w, http.StatusBadRequest, err)
+		s.Logger.WithError(err).Error("negotiating graph format")
+		return
+	}
+
+	var requestBody struct {
+		Range        timeRange     `json:"range"`
+		Targets      []queryTarget `json:"targets"`
+		AdhocFilters []adhocFilter `json:"adhocFilters"`
+	}
+	if err := DecodeBodyFn(r.Body, &requestBody); err != nil {
+		if err != io.EOF {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %v", err))
+			s.Logger.WithError(err).Error("decoding graph body")
+			return
+		}
+		// no body: a single target from the query string
+		requestBody.Targets = []queryTarget{{Target: r.URL.Query().Get("target"), RefID: "A"}}
+	}
+
+	adhoc, err := volumeSchema.adhocRowFilter(requestBody.AdhocFilters)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		s.Logger.WithError(err).Error("parsing ad-hoc filters")
+		return
+	}
+
+	volumes, err := s.VolumeFinder.GetPersistentVolumes(ctx)
+	if err != nil {
+		s.writeError(w, errorStatus(err), errors.New("getting volumes"))
+		s.Logger.WithError(err).Error("getting persistent volumes")
+		return
+	}
+	rows := make([][]string, 0, len(volumes))
+	for _, volume := range volumes {
+		rows = append(rows, volumeRow(newTableRow(volume)))
+	}
+
+	frames := make([]interface{}, 0, 2*len(requestBody.Targets))
+	// the text formats render every volume selected by a visible target once
+	selected := make([]bool, len(volumes))
+	var included []string
+	for _, target := range requestBody.Targets {
+		if target.Hide {
+			continue
+		}
+		query, include, err := parseGraphTarget(target.Target)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("target %s: %v", target.RefID, err))
+			s.Logger.WithError(err).Errorf("parsing graph target: %s", target.Target)
+			return
+		}
+
+		filter := allFilter{volumeSchema.rowFilter(query, requestBody.Range), adhoc}
+		graph := newTopologyGraph()
+		for i, volume := range volumes {
+			if filter.match(rows[i]) {
+				graph.add(volume, include)
+				selected[i] = true
+			}
+		}
+		included = append(included, include...)
+		s.Logger.WithFields(logrus.Fields{
+			"refId": target.RefID,
+			"nodes": len(graph.nodes),
+			"edges": len(graph.edges),
+		}).Debug("generating graph response")
+		frames = append(frames, graph.nodeFrame(target.RefID), graph.edgeFrame(target.RefID))
+	}
+
+	union := newTopologyGraph()
+	for i, volume := range volumes {
+		if selected[i] {
+			union.add(volume, included)
+		}
+	}
+
+	switch format {
+	case graphDOT:
+		s.writeText(w, graphContentTypes[format], union.dot())
+	case graphMermaid:
+		s.writeText(w, graphContentTypes[format], union.mermaid())
+	default:
+		s.writeJSON(w, frames)
+	}
+}
+
+// parseGraphTarget parses a graph target: a volume target filter that may list the optional node kinds to include
+func parseGraphTarget(target string) (targetQuery, []string, error) {
+	if strings.TrimSpace(target) == "" {
+		query, err := volumeSchema.parseTarget(target)
+		return query, nil, err
+	}
+
+	fields, err := decodeTarget(target)
+	if err != nil {
+		return targetQuery{}, nil, err
+	}
+
+	var include []string
+	if data, ok := fields[includeKey]; ok {
+		names, err := parseNames(data)
+		if err != nil {
+			return targetQuery{}, nil, fmt.Errorf("%q %v", includeKey, err)
+		}
+		for _, name := range names {
+			index, err := volumeSchema.column(name)
+			if err != nil || !slices.Contains(optionalGraphKinds, volumeSchema[index].Key) {
+				return targetQuery{}, nil, fmt.Errorf("invalid %s %q; valid values are %s", includeKey, name, strings.Join(optionalGraphKinds, ", "))
+			}
+			include = append(include, volumeSchema[index].Key)
+		}
+		delete(fields, includeKey)
+	}
+
+	query, err := volumeSchema.parseTargetFields(fields)
+	if err != nil {
+		return targetQuery{}, nil, err
+	}
+	if query.aggregation != nil {
+		return targetQuery{}, nil, fmt.Errorf("aggregation targets cannot be rendered as a graph")
+	}
+	return query, include, nil
+}
+
+// newTopologyGraph returns an empty graph
+func newTopologyGraph() *topologyGraph {
+	return &topologyGraph{nodes: make(map[string]*graphNode), edges: make(map[[2]string]struct{})}
+}
+
+// add adds the objects of a volume and their relations to the graph; pods and nodes are only added when included
+func (g *topologyGraph) add(volume k8s.VolumeInfo, include []string) {
+	g.volumes++
+	cluster := volume.Cluster
+	namespace := g.node("namespace", volume.Namespace, cluster, volume.Namespace)
+	claim := g.node("persistent_volume_claim", volume.VolumeClaimName, cluster, volume.Namespace, volume.VolumeClaimName)
+	pv := g.node("persistent_volume", volume.PersistentVolume, cluster, volume.PersistentVolume)
+	class := g.node("storage_class", volume.StorageClass, cluster, volume.StorageClass)
+	driver := g.node("csi_driver", volume.Driver, "", volume.Driver)
+	system := g.node("storage_system", volume.StorageSystem, "", volume.StorageSystem)
+	pool := ""
+	if volume.StoragePoolName != "N/A" {
+		pool = g.node("storage_pool", volume.StoragePoolName, "", volume.StorageSystem, volume.StoragePoolName)
+	}
+	if pv != "" {
+		g.nodes[pv].size = volume.ProvisionedSize
+	}
+
+	g.edge(namespace, claim)
+	g.edge(claim, pv)
+	// unbound claims reach their storage class directly
+	consumer := pv
+	if consumer == "" {
+		consumer = claim
+	}
+	g.edge(consumer, class)
+	g.edge(class, driver)
+	g.edge(driver, system)
+	if pool != "" {
+		g.edge(pv, pool)
+		g.edge(pool, system)
+	} else {
+		g.edge(pv, system)
+	}
+
+	withPods := slices.Contains(include, "pod")
+	withNodes := slices.Contains(include, "node")
+	for _, p := range volume.Pods {
+		user := claim
+		if withPods {
+			user = g.node("pod", p.Name, cluster, volume.Namespace, p.Name)
+			g.edge(user, claim)
+		}
+		if withNodes {
+			g.edge(user, g.node("node", p.Node, cluster, p.Node))
+		}
+	}
+}
+
+// node adds a node unless its name is empty and returns its id; every node is identified by its kind and the names
+// that make it unique, including the cluster for namespaced and cluster-scoped objects
+func (g *topologyGraph) node(kind, title, cluster string, names ...string) string {
+	if title == "" {
+		return ""
+	}
+	if cluster != "" {
+		names = append([]string{cluster}, names...)
+	}
+	id := kind + ":" + strings.Join(names, "/")
+	n, ok := g.nodes[id]
+	if !ok {
+		n = &graphNode{id: id, kind: kind, title: title, cluster: cluster}
+		g.nodes[id] = n
+	}
+	if n.last != g.volumes {
+		n.last = g.volumes
+		n.volumes++
+	}
+	return id
+}
+
+// edge adds an edge between two nodes; edges to a missing node are ignored
+func (g *topologyGraph) edge(source, target string) {
+	if source == "" || target == "" {
+		return
+	}
+	g.edges[[2]string{source, target}] = struct{}{}
+}
+
+// sortedNodes returns the nodes by kind and title
+func (g *topologyGraph) sortedNodes() []*graphNode {
+	nodes := make([]*graphNode, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := slices.Index(graphKinds, nodes[i].kind), slices.Index(graphKinds, nodes[j].kind)
+		if a != b {
+			return a < b
+		}
+		if nodes[i].title != nodes[j].title {
+			return nodes[i].title < nodes[j].title
+		}
+		return nodes[i].id < nodes[j].id
+	})
+	return nodes
+}
+
+// sortedEdges returns the edges by source and target
+func (g *topologyGraph) sortedEdges() [][2]string {
+	edges := make([][2]string, 0, len(g.edges))
+	for edge := range g.edges {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i][0] != edges[j][0] {
+			return edges[i][0] < edges[j][0]
+		}
+		return edges[i][1] < edges[j][1]
+	})
+	return edges
+}
+
+// subtitle returns the kind of the node as the title of its volume column
+func (n *graphNode) subtitle() string {
+	index, _ := volumeSchema.column(n.kind)
+	return volumeSchema[index].Text
+}
+
+// mainStat returns the provisioned size of persistent volumes and the number of volumes of other nodes
+func (n *graphNode) mainStat() string {
+	if n.kind == "persistent_volume" {
+		return n.size
+	}
+	if n.volumes == 1 {
+		return "1 volume"
+	}
+	return fmt.Sprintf("%d volumes", n.volumes)
+}
+
+// nodeFrame returns the nodes frame of a Grafana Node Graph panel
+func (g *topologyGraph) nodeFrame(refID string) tableFrame {
+	frame := tableFrame{
+		RefID: refID,
+		Columns: []frameColumn{
+			{Text: "id", Type: columnString},
+			{Text: "title", Type: columnString},
+			{Text: "subtitle", Type: columnString},
+			{Text: "mainstat", Type: columnString},
+			{Text: "detail__cluster", Type: columnString},
+		},
+		Rows: make([][]interface{}, 0, len(g.nodes)),
+		Type: targetTable,
+	}
+	for _, n := range g.sortedNodes() {
+		frame.Rows = append(frame.Rows, []interface{}{n.id, n.title, n.subtitle(), n.mainStat(), n.cluster})
+	}
+	return frame
+}
+
+// edgeFrame returns the edges frame of a Grafana Node Graph panel
+func (g *topologyGraph) edgeFrame(refID string) tableFrame {
+	frame := tableFrame{
+		RefID: refID,
+		Columns: []frameColumn{
+			{Text: "id", Type: columnString},
+			{Text: "source", Type: columnString},
+			{Text: "target", Type: columnString},
+		},
+		Rows: make([][]interface{}, 0, len(g.edges)),
+		Type: targetTable,
+	}
+	for _, edge := range g.sortedEdges() {
+		frame.Rows = append(frame.Rows, []interface{}{edge[0] + "->" + edge[1], edge[0], edge[1]})
+	}
+	return frame
+}
+
+// caption returns the second line of the text displayed in a node: its kind and cluster
+func (n *graphNode) caption() string {
+	if n.cluster == "" {
+		return n.subtitle()
+	}
+	return n.subtitle() + " (" + n.cluster + ")"
+}
+
+// dot returns the graph in the Graphviz DOT language
+func (g *topologyGraph) dot() string {
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	var b strings.Builder
+	b.WriteString("digraph topology {\n  rankdir=LR;\n  node [shape=box, style=rounded];\n")
+	for _, n := range g.sortedNodes() {
+		fmt.Fprintf(&b, "  \"%s\" [label=\"%s\"];\n", quote.Replace(n.id), quote.Replace(n.title)+`\n`+quote.Replace(n.caption()))
+	}
+	for _, edge := range g.sortedEdges() {
+		fmt.Fprintf(&b, "  \"%s\" -> \"%s\";\n", quote.Replace(edge[0]), quote.Replace(edge[1]))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaid returns the graph as a Mermaid flowchart; nodes get short ids since Mermaid ids cannot hold every character
+func (g *topologyGraph) mermaid() string {
+	escape := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := make(map[string]string, len(g.nodes))
+	for i, n := range g.sortedNodes() {
+		ids[n.id] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n.id], escape.Replace(n.title)+"<br/>"+escape.Replace(n.caption()))
+	}
+	for _, edge := range g.sortedEdges() {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[edge[0]], ids[edge[1]])
+	}
+	return b.String()
+}
+
+// writeText writes a text response
+func (s *Service) writeText(w http.ResponseWriter, contentType string, text string) {
+	w.Header().Set("Content-Type", contentType)
+	if _, err := HTTPWrite(&w, []byte(text)); err != nil {
+		s.Logger.WithError(err).Error("writing response")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
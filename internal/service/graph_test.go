@@ -0,0 +1,235 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphHandler(t *testing.T) {
+	tests := map[string]struct {
+		method         string
+		query          string
+		body           string
+		expectedStatus int
+		expectedType   string
+		expected       string
+	}{
+		"node graph frames": {
+			method:         http.MethodPost,
+			body:           `{"targets": [{"target": "{\"Namespace\": \"ns-2\"}", "refId": "A"}, {"target": "{}", "refId": "B", "hide": true}]}`,
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/json; charset=UTF-8",
+			expected: `[
+				{"refId": "A", "type": "table", "columns": [
+					{"text": "id", "type": "string"}, {"text": "title", "type": "string"}, {"text": "subtitle", "type": "string"},
+					{"text": "mainstat", "type": "string"}, {"text": "detail__cluster", "type": "string"}],
+				"rows": [
+					["namespace:ns-2", "ns-2", "Namespace", "1 volume", ""],
+					["persistent_volume_claim:ns-2/pvc-2", "pvc-2", "Persistent Volume Claim", "1 volume", ""],
+					["persistent_volume:pv-2", "pv-2", "Persistent Volume", "16Gi", ""],
+					["storage_class:vxflexos", "vxflexos", "Storage Class", "1 volume", ""],
+					["csi_driver:csi-vxflexos.dellemc.com", "csi-vxflexos.dellemc.com", "CSI Driver", "1 volume", ""],
+					["storage_pool:7045c4cc20dffc0f/pool-1", "pool-1", "Storage Pool", "1 volume", ""],
+					["storage_system:7045c4cc20dffc0f", "7045c4cc20dffc0f", "Storage System", "1 volume", ""]]},
+				{"refId": "A", "type": "table", "columns": [
+					{"text": "id", "type": "string"}, {"text": "source", "type": "string"}, {"text": "target", "type": "string"}],
+				"rows": [
+					["csi_driver:csi-vxflexos.dellemc.com->storage_system:7045c4cc20dffc0f", "csi_driver:csi-vxflexos.dellemc.com", "storage_system:7045c4cc20dffc0f"],
+					["namespace:ns-2->persistent_volume_claim:ns-2/pvc-2", "namespace:ns-2", "persistent_volume_claim:ns-2/pvc-2"],
+					["persistent_volume:pv-2->storage_class:vxflexos", "persistent_volume:pv-2", "storage_class:vxflexos"],
+					["persistent_volume:pv-2->storage_pool:7045c4cc20dffc0f/pool-1", "persistent_volume:pv-2", "storage_pool:7045c4cc20dffc0f/pool-1"],
+					["persistent_volume_claim:ns-2/pvc-2->persistent_volume:pv-2", "persistent_volume_claim:ns-2/pvc-2", "persistent_volume:pv-2"],
+					["storage_class:vxflexos->csi_driver:csi-vxflexos.dellemc.com", "storage_class:vxflexos", "csi_driver:csi-vxflexos.dellemc.com"],
+					["storage_pool:7045c4cc20dffc0f/pool-1->storage_system:7045c4cc20dffc0f", "storage_pool:7045c4cc20dffc0f/pool-1", "storage_system:7045c4cc20dffc0f"]]}
+			]`,
+		},
+		"dot": {
+			method:         http.MethodGet,
+			query:          "?format=dot&target=" + url.QueryEscape(`{"namespace": "ns-1"}`),
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/vnd.graphviz; charset=UTF-8",
+			expected: `digraph topology {
+  rankdir=LR;
+  node [shape=box, style=rounded];
+  "namespace:ns-1" [label="ns-1\nNamespace"];
+  "persistent_volume_claim:ns-1/pvc-1" [label="pvc-1\nPersistent Volume Claim"];
+  "persistent_volume:pv-1" [label="pv-1\nPersistent Volume"];
+  "storage_class:powerstore" [label="powerstore\nStorage Class"];
+  "csi_driver:csi-powerstore.dellemc.com" [label="csi-powerstore.dellemc.com\nCSI Driver"];
+  "storage_system:10.0.0.1" [label="10.0.0.1\nStorage System"];
+  "csi_driver:csi-powerstore.dellemc.com" -> "storage_system:10.0.0.1";
+  "namespace:ns-1" -> "persistent_volume_claim:ns-1/pvc-1";
+  "persistent_volume:pv-1" -> "storage_class:powerstore";
+  "persistent_volume:pv-1" -> "storage_system:10.0.0.1";
+  "persistent_volume_claim:ns-1/pvc-1" -> "persistent_volume:pv-1";
+  "storage_class:powerstore" -> "csi_driver:csi-powerstore.dellemc.com";
+}
+`,
+		},
+		"mermaid with pods and nodes": {
+			method:         http.MethodGet,
+			query:          "?format=mermaid&target=" + url.QueryEscape(`{"namespace": "ns-1", "$include": ["Pod", "node"]}`),
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/plain; charset=UTF-8",
+			expected: `flowchart LR
+  n0["ns-1<br/>Namespace"]
+  n1["web-0<br/>Pod"]
+  n2["web-1<br/>Pod"]
+  n3["pvc-1<br/>Persistent Volume Claim"]
+  n4["pv-1<br/>Persistent Volume"]
+  n5["powerstore<br/>Storage Class"]
+  n6["csi-powerstore.dellemc.com<br/>CSI Driver"]
+  n7["10.0.0.1<br/>Storage System"]
+  n8["node-1<br/>Node"]
+  n9["node-2<br/>Node"]
+  n6 --> n7
+  n0 --> n3
+  n4 --> n5
+  n4 --> n7
+  n3 --> n4
+  n1 --> n8
+  n1 --> n3
+  n2 --> n9
+  n2 --> n3
+  n5 --> n6
+`,
+		},
+		"unsupported format": {
+			method:         http.MethodGet,
+			query:          "?format=svg",
+			expectedStatus: http.StatusBadRequest,
+			expectedType:   "application/json; charset=UTF-8",
+			expected:       `{"message": "unsupported format \"svg\"; valid formats are json, dot and mermaid"}`,
+		},
+		"invalid include": {
+			method:         http.MethodGet,
+			query:          "?target=" + url.QueryEscape(`{"$include": "namespace"}`),
+			expectedStatus: http.StatusBadRequest,
+			expectedType:   "application/json; charset=UTF-8",
+			expected:       `{"message": "target A: invalid $include \"namespace\"; valid values are pod, node"}`,
+		},
+		"aggregation target": {
+			method:         http.MethodPost,
+			body:           `{"targets": [{"target": "{\"$groupBy\": \"Namespace\"}", "refId": "A"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedType:   "application/json; charset=UTF-8",
+			expected:       `{"message": "target A: aggregation targets cannot be rendered as a graph"}`,
+		},
+		"invalid body": {
+			method:         http.MethodPost,
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedType:   "application/json; charset=UTF-8",
+			expected:       `{"message": "invalid body: unexpected EOF"}`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).AnyTimes().Return(testVolumes(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			res, body := request(t, tc.method, ctx.server.URL+"/graph"+tc.query, "", tc.body)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			assert.Equal(t, tc.expectedType, res.Header.Get("Content-Type"))
+			if tc.expectedType == "application/json; charset=UTF-8" {
+				assert.JSONEq(t, tc.expected, string(body))
+			} else {
+				assert.Equal(t, tc.expected, string(body))
+			}
+		})
+	}
+}
+
+func TestGraphEscapedTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(2).Return(testVolumes(), nil)
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	_, plain := request(t, http.MethodGet, ctx.server.URL+"/graph?format=dot&target="+url.QueryEscape(`{"namespace": "ns-1", "$include": "pod"}`), "", "")
+	res, escaped := request(t, http.MethodGet, ctx.server.URL+"/graph?format=dot&target="+url.QueryEscape(`{\"namespace\": \"ns-1\", \"$include\": \"pod\"}`), "", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, string(escaped), "Pod")
+	assert.Equal(t, string(plain), string(escaped))
+}
+
+func TestGraphClusters(t *testing.T) {
+	volumes := append(testVolumes(), testVolumes()[0])
+	volumes[0].Cluster = "east"
+	volumes[2].Cluster = "west"
+
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(volumes, nil)
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	res, body := request(t, http.MethodGet, ctx.server.URL+"/graph?format=mermaid&target="+url.QueryEscape(`{"Storage System": "10.0.0.1"}`), "", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	// the storage objects are shared by the clusters, the Kubernetes objects are not
+	assert.Equal(t, `flowchart LR
+  n0["ns-1<br/>Namespace (east)"]
+  n1["ns-1<br/>Namespace (west)"]
+  n2["pvc-1<br/>Persistent Volume Claim (east)"]
+  n3["pvc-1<br/>Persistent Volume Claim (west)"]
+  n4["pv-1<br/>Persistent Volume (east)"]
+  n5["pv-1<br/>Persistent Volume (west)"]
+  n6["powerstore<br/>Storage Class (east)"]
+  n7["powerstore<br/>Storage Class (west)"]
+  n8["csi-powerstore.dellemc.com<br/>CSI Driver"]
+  n9["10.0.0.1<br/>Storage System"]
+  n8 --> n9
+  n0 --> n2
+  n1 --> n3
+  n4 --> n6
+  n4 --> n9
+  n5 --> n7
+  n5 --> n9
+  n2 --> n4
+  n3 --> n5
+  n6 --> n8
+  n7 --> n8
+`, string(body))
+}
+
+func TestGraphError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(nil, errors.New("error"))
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	res, _ := request(t, http.MethodGet, ctx.server.URL+"/graph", "", "")
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+}
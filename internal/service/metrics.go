@@ -0,0 +1,119 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	tracer "github.com/dell/karavi-topology/internal/tracers"
+)
+
+// Names of the topology metrics
+const (
+	volumeInfoMetric             = "karavi_topology_volume_info"
+	volumeProvisionedBytesMetric = "karavi_topology_volume_provisioned_bytes"
+)
+
+// metricsContentType is the content type of the Prometheus text exposition format
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelValueReplacer escapes label values for the Prometheus text exposition format
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricsRequest exposes the topology of every volume in the Prometheus text exposition format
+func (s *Service) metricsRequest(w http.ResponseWriter, _ *http.Request) {
+	ctx, span := tracer.GetTracer(context.Background(), "metricsRequest")
+	defer span.End()
+
+	volumes, err := s.VolumeFinder.GetPersistentVolumes(ctx)
+	if err != nil {
+		w.WriteHeader(errorStatus(err))
+		s.Logger.WithError(err).Error("getting persistent volumes")
+		return
+	}
+
+	table := make([]Table, 0, len(volumes))
+	for _, volume := range volumes {
+		// unbound claims have no volume yet, so they would be series without a persistent volume
+		if volume.PersistentVolume != "" {
+			table = append(table, newTableRow(volume))
+		}
+	}
+
+	var output bytes.Buffer
+	fmt.Fprintf(&output, "# HELP %s Topology of a persistent volume provisioned by a Dell CSI driver.\n", volumeInfoMetric)
+	fmt.Fprintf(&output, "# TYPE %s gauge\n", volumeInfoMetric)
+	for _, row := range table {
+		fmt.Fprintf(&output, "%s{%s} 1\n", volumeInfoMetric, volumeInfoLabels(row))
+	}
+
+	fmt.Fprintf(&output, "# HELP %s Provisioned size of a persistent volume in bytes.\n", volumeProvisionedBytesMetric)
+	fmt.Fprintf(&output, "# TYPE %s gauge\n", volumeProvisionedBytesMetric)
+	for _, row := range table {
+		size, ok := parseBytes(row.ProvisionedSize)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&output, "%s{%s} %d\n", volumeProvisionedBytesMetric, volumeLabels(row), size)
+	}
+
+	w.Header().Set("Content-Type", metricsContentType)
+	if _, err = HTTPWrite(&w, output.Bytes()); err != nil {
+		s.Logger.WithError(err).Error("writing metrics")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// volumeLabels returns the labels that identify a volume; they are shared by every series of the volume for joins.
+// The cluster label is only set for volumes found in a named cluster.
+func volumeLabels(row Table) string {
+	labels := [][2]string{
+		{"persistent_volume", row.PersistentVolume},
+		{"namespace", row.Namespace},
+		{"persistent_volume_claim", row.PersistentVolumeClaim},
+		{"storage_class", row.StorageClass},
+	}
+	if row.Cluster != "" {
+		labels = append(labels, [2]string{"cluster", row.Cluster})
+	}
+	return formatLabels(labels)
+}
+
+// volumeInfoLabels returns the labels of the volume info series
+func volumeInfoLabels(row Table) string {
+	return volumeLabels(row) + "," + formatLabels([][2]string{
+		{"csi_driver", row.CSIDriver},
+		{"storage_system", row.StorageSystem},
+		{"storage_pool", row.StoragePool},
+		{"storage_system_volume_name", row.StorageSystemVolumeName},
+		{"protocol", row.Protocol},
+		{"status", row.Status},
+	})
+}
+
+// formatLabels formats name and value pairs as a Prometheus label list
+func formatLabels(labels [][2]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, label[0], labelValueReplacer.Replace(label[1])))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,110 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	tests := map[string]struct {
+		volumes        []k8s.VolumeInfo
+		err            error
+		expectedStatus int
+		expected       string
+	}{
+		"volumes": {
+			volumes:        testVolumes(),
+			expectedStatus: http.StatusOK,
+			expected: `# HELP karavi_topology_volume_info Topology of a persistent volume provisioned by a Dell CSI driver.
+# TYPE karavi_topology_volume_info gauge
+karavi_topology_volume_info{persistent_volume="pv-1",namespace="ns-1",persistent_volume_claim="pvc-1",storage_class="powerstore",csi_driver="csi-powerstore.dellemc.com",storage_system="10.0.0.1",storage_pool="N/A",storage_system_volume_name="pv-1",protocol="scsi",status="Bound"} 1
+karavi_topology_volume_info{persistent_volume="pv-2",namespace="ns-2",persistent_volume_claim="pvc-2",storage_class="vxflexos",csi_driver="csi-vxflexos.dellemc.com",storage_system="7045c4cc20dffc0f",storage_pool="pool-1",storage_system_volume_name="k8s-pv-2",protocol="scsi",status="Released"} 1
+# HELP karavi_topology_volume_provisioned_bytes Provisioned size of a persistent volume in bytes.
+# TYPE karavi_topology_volume_provisioned_bytes gauge
+karavi_topology_volume_provisioned_bytes{persistent_volume="pv-1",namespace="ns-1",persistent_volume_claim="pvc-1",storage_class="powerstore"} 8589934592
+karavi_topology_volume_provisioned_bytes{persistent_volume="pv-2",namespace="ns-2",persistent_volume_claim="pvc-2",storage_class="vxflexos"} 17179869184
+`,
+		},
+		"escaped labels and unparsable size": {
+			volumes: []k8s.VolumeInfo{
+				{PersistentVolume: "pv-\"1\"", Namespace: "ns\\1", ProvisionedSize: "unknown"},
+			},
+			expectedStatus: http.StatusOK,
+			expected: `# HELP karavi_topology_volume_info Topology of a persistent volume provisioned by a Dell CSI driver.
+# TYPE karavi_topology_volume_info gauge
+karavi_topology_volume_info{persistent_volume="pv-\"1\"",namespace="ns\\1",persistent_volume_claim="",storage_class="",csi_driver="",storage_system="",storage_pool="",storage_system_volume_name="",protocol="",status=""} 1
+# HELP karavi_topology_volume_provisioned_bytes Provisioned size of a persistent volume in bytes.
+# TYPE karavi_topology_volume_provisioned_bytes gauge
+`,
+		},
+		"unbound claims are left out": {
+			volumes: []k8s.VolumeInfo{
+				{Namespace: "ns-1", VolumeClaimName: "pvc-1", PersistentVolumeStatus: k8s.PersistentVolumeStatusUnbound, ProvisionedSize: "8Gi"},
+			},
+			expectedStatus: http.StatusOK,
+			expected: `# HELP karavi_topology_volume_info Topology of a persistent volume provisioned by a Dell CSI driver.
+# TYPE karavi_topology_volume_info gauge
+# HELP karavi_topology_volume_provisioned_bytes Provisioned size of a persistent volume in bytes.
+# TYPE karavi_topology_volume_provisioned_bytes gauge
+`,
+		},
+		"cluster label": {
+			volumes: []k8s.VolumeInfo{
+				{PersistentVolume: "pv-1", Namespace: "ns-1", ProvisionedSize: "1Gi", Cluster: "east"},
+			},
+			expectedStatus: http.StatusOK,
+			expected: `# HELP karavi_topology_volume_info Topology of a persistent volume provisioned by a Dell CSI driver.
+# TYPE karavi_topology_volume_info gauge
+karavi_topology_volume_info{persistent_volume="pv-1",namespace="ns-1",persistent_volume_claim="",storage_class="",cluster="east",csi_driver="",storage_system="",storage_pool="",storage_system_volume_name="",protocol="",status=""} 1
+# HELP karavi_topology_volume_provisioned_bytes Provisioned size of a persistent volume in bytes.
+# TYPE karavi_topology_volume_provisioned_bytes gauge
+karavi_topology_volume_provisioned_bytes{persistent_volume="pv-1",namespace="ns-1",persistent_volume_claim="",storage_class="",cluster="east"} 1073741824
+`,
+		},
+		"error getting volumes": {
+			err:            errors.New("error"),
+			expectedStatus: http.StatusInternalServerError,
+			expected:       "",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(tc.volumes, tc.err)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			res, body := request(t, http.MethodGet, ctx.server.URL+"/metrics", "", "")
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			assert.Equal(t, tc.expected, string(body))
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", res.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
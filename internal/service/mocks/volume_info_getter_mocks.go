@@ -65,3 +65,32 @@ func (mr *MockVolumeInfoGetterMockRecorder) GetPersistentVolumes(arg0 interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersistentVolumes", reflect.TypeOf((*MockVolumeInfoGetter)(nil).GetPersistentVolumes), arg0)
 }
+
+// GetVolumeSnapshots mocks base method.
+func (m *MockVolumeInfoGetter) GetVolumeSnapshots(arg0 context.Context) ([]k8s.SnapshotInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeSnapshots", arg0)
+	ret0, _ := ret[0].([]k8s.SnapshotInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeSnapshots indicates an expected call of GetVolumeSnapshots.
+func (mr *MockVolumeInfoGetterMockRecorder) GetVolumeSnapshots(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeSnapshots", reflect.TypeOf((*MockVolumeInfoGetter)(nil).GetVolumeSnapshots), arg0)
+}
+
+// Ready mocks base method.
+func (m *MockVolumeInfoGetter) Ready() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockVolumeInfoGetterMockRecorder) Ready() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockVolumeInfoGetter)(nil).Ready))
+}
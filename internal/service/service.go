@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"expvar"
 	"fmt"
 	"io"
@@ -34,6 +35,7 @@ import (
 
 	tracer "github.com/dell/karavi-topology/internal/tracers"
 	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/util/duration"
 )
 
 const (
@@ -43,11 +45,13 @@ const (
 // Service contains data required by the service
 type Service struct {
 	VolumeFinder VolumeInfoGetter
-	CertFile     string
-	KeyFile      string
-	Port         int
-	Logger       *logrus.Logger
-	EnableDebug  bool
+	// VolumeHistory serves the change history; the history endpoints answer 404 when it is nil
+	VolumeHistory VolumeEventGetter
+	CertFile      string
+	KeyFile       string
+	Port          int
+	Logger        *logrus.Logger
+	EnableDebug   bool
 }
 
 // VolumeInfoGetter is an interface used to get a list of volume information
@@ -55,6 +59,15 @@ type Service struct {
 //go:generate mockgen -destination=mocks/volume_info_getter_mocks.go -package=mocks github.com/dell/karavi-topology/internal/service VolumeInfoGetter
 type VolumeInfoGetter interface {
 	GetPersistentVolumes(ctx context.Context) ([]k8s.VolumeInfo, error)
+	GetVolumeSnapshots(ctx context.Context) ([]k8s.SnapshotInfo, error)
+	Ready() bool
+}
+
+// VolumeEventGetter is an interface used to get the recorded volume events
+//
+//go:generate mockgen -destination=mocks/volume_event_getter_mocks.go -package=mocks github.com/dell/karavi-topology/internal/service VolumeEventGetter
+type VolumeEventGetter interface {
+	GetVolumeEvents(since time.Time) []k8s.VolumeEvent
 }
 
 // Run will start the service and listen for HTTP requests
@@ -106,7 +119,29 @@ func (s *Service) Routes() *mux.Router {
 	s.Logger.Debug("setting up routes")
 	r := mux.NewRouter()
 	r.HandleFunc("/", s.logHandler(s.rootRequest))
+	r.HandleFunc("/ready", s.logHandler(s.readyRequest))
 	r.HandleFunc("/topology.json", s.logHandler(s.queryRequest))
+	r.HandleFunc("/query", s.logHandler(s.queryRequest))
+	r.HandleFunc("/search", s.logHandler(s.searchRequest))
+	r.HandleFunc("/annotations", s.logHandler(s.annotationsRequest))
+	r.HandleFunc("/tag-keys", s.logHandler(s.tagKeysRequest))
+	r.HandleFunc("/tag-values", s.logHandler(s.tagValuesRequest))
+	r.HandleFunc("/graph", s.logHandler(s.graphRequest))
+	r.HandleFunc("/metrics", s.logHandler(s.metricsRequest)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/volumes", s.logHandler(s.listVolumesRequest)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/volumes/{name}", s.logHandler(s.getVolumeRequest)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/snapshots", s.logHandler(s.listSnapshotsRequest)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/snapshots/{name}", s.logHandler(s.getSnapshotRequest)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/storage-systems", s.logHandler(s.listStorageSystemsRequest)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/storage-systems/{name}", s.logHandler(s.getStorageSystemRequest)).Methods(http.MethodGet)
+	r.HandleFunc("/snapshots/", s.logHandler(s.rootRequest))
+	r.HandleFunc("/snapshots/query", s.logHandler(s.snapshotQueryRequest))
+	r.HandleFunc("/snapshots/search", s.logHandler(s.snapshotSearchRequest))
+	r.HandleFunc("/snapshots/tag-keys", s.logHandler(s.snapshotTagKeysRequest))
+	r.HandleFunc("/snapshots/tag-values", s.logHandler(s.snapshotTagValuesRequest))
+	r.HandleFunc("/api/v1/events", s.logHandler(s.listEventsRequest)).Methods(http.MethodGet)
+	r.HandleFunc("/events/", s.logHandler(s.rootRequest))
+	r.HandleFunc("/events/annotations", s.logHandler(s.eventAnnotationsRequest))
 	if s.EnableDebug {
 		r.HandleFunc("/debug/pprof/", pprof.Index)
 		r.HandleFunc("/debug/pprof/{action}", pprof.Index)
@@ -132,20 +167,37 @@ func (s *Service) rootRequest(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// readyRequest reports whether the volume cache has completed its initial sync
+func (s *Service) readyRequest(w http.ResponseWriter, _ *http.Request) {
+	if !s.VolumeFinder.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 func (s *Service) queryRequest(w http.ResponseWriter, r *http.Request) {
+	s.queryTable(w, r, s.volumeTable())
+}
+
+// queryTable answers a Grafana query on a topology table: one response per visible target, or the bare rows matching the
+// ad-hoc filters when the request has no targets
+func (s *Service) queryTable(w http.ResponseWriter, r *http.Request, table topologyTable) {
 	ctx, span := tracer.GetTracer(context.Background(), "GetPersistentVolumes")
 	defer span.End()
 
-	volumes, err := s.VolumeFinder.GetPersistentVolumes(ctx)
+	rows, err := table.rows(ctx)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		s.Logger.WithError(err).Error("getting persistent volumes")
+		w.WriteHeader(errorStatus(err))
+		s.Logger.WithError(err).Errorf("getting %s", table.name)
 		return
 	}
-	s.Logger.WithField("volumes", len(volumes)).Debug("volumefinder returned persistent volumes")
+	s.Logger.WithField(table.name, len(rows)).Debug("volumefinder returned rows")
 
 	var requestBody struct {
-		Targets []map[string]interface{} `json:"targets"`
+		Range        timeRange     `json:"range"`
+		Targets      []queryTarget `json:"targets"`
+		AdhocFilters []adhocFilter `json:"adhocFilters"`
 	}
 
 	if err := DecodeBodyFn(r.Body, &requestBody); err != nil {
@@ -154,25 +206,66 @@ func (s *Service) queryRequest(w http.ResponseWriter, r *http.Request) {
 			s.Logger.WithError(err).Error("decoding body")
 			return
 		}
-		requestBody.Targets = [](map[string]interface{}){} // no body
+		requestBody.Targets = []queryTarget{} // no body
 	}
 
-	var lookUp []map[string]string
-	for _, v := range requestBody.Targets {
-		m := make(map[string]string)
-		target := strings.Replace(v["target"].(string), "\\", "", -1)
-		if err = UnMarshalFn([]byte(target), &m); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			s.Logger.WithError(err).Errorf("unmarshalling target: %s", target)
-			return
+	format, err := responseFormat(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		s.Logger.WithError(err).Error("negotiating response format")
+		return
+	}
+
+	adhoc, err := table.schema.adhocRowFilter(requestBody.AdhocFilters)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		s.Logger.WithError(err).Error("parsing ad-hoc filters")
+		return
+	}
+
+	var response interface{}
+	var exported [][]string // rows of every visible target, exported once each
+	if len(requestBody.Targets) == 0 {
+		filtered := filterRows(rows, adhoc)
+		s.Logger.WithField("table", len(filtered)).Debug("generating table response")
+		response = table.schema.objects(filtered)
+		exported = filtered
+	} else {
+		responses := make([]interface{}, 0, len(requestBody.Targets))
+		for _, target := range requestBody.Targets {
+			if target.Hide {
+				continue
+			}
+			query, err := table.schema.parseTarget(target.Target)
+			if err != nil {
+				s.writeError(w, http.StatusBadRequest, fmt.Errorf("target %s: %v", target.RefID, err))
+				s.Logger.WithError(err).Errorf("parsing target: %s", target.Target)
+				return
+			}
+			if query.aggregation != nil && format != formatJSON {
+				err := fmt.Errorf("target %s: aggregation targets cannot be exported as %s", target.RefID, format)
+				s.writeError(w, http.StatusBadRequest, err)
+				s.Logger.WithError(err).Error("exporting table")
+				return
+			}
+
+			filtered := filterRows(rows, allFilter{table.schema.rowFilter(query, requestBody.Range), adhoc})
+			s.Logger.WithFields(logrus.Fields{
+				"refId": target.RefID,
+				"table": len(filtered),
+			}).Debug("generating target response")
+			responses = append(responses, target.response(table, query, filtered)...)
+			exported = appendUnique(exported, filtered)
 		}
-		lookUp = append(lookUp, m)
+		response = responses
 	}
 
-	table := generateVolumeTableJSON(volumes, lookUp)
-	s.Logger.WithField("table", len(table)).Debug("generating table response")
+	if format != formatJSON {
+		s.writeTableExport(w, format, table, exported)
+		return
+	}
 
-	output, err := MarshalFn(table)
+	output, err := MarshalFn(response)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		s.Logger.WithError(err).Error("marshalling table response")
@@ -209,6 +302,14 @@ var HTTPWrite = func(w *http.ResponseWriter, data []byte) (int, error) {
 	return (*w).Write([]byte(data))
 }
 
+// errorStatus returns the status of a failure to get the topology: 503 until the caches have synced, 500 otherwise
+func errorStatus(err error) int {
+	if errors.Is(err, k8s.ErrCacheNotSynced) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
 // Table contains the
 type Table struct {
 	Namespace               string `json:"namespace"`
@@ -223,55 +324,145 @@ type Table struct {
 	StoragePool             string `json:"storage_pool"`
 	StorageSystem           string `json:"storage_system"`
 	Protocol                string `json:"protocol"`
+	Pod                     string `json:"pod"`
+	OwnerKind               string `json:"owner_kind"`
+	Owner                   string `json:"owner"`
+	Node                    string `json:"node"`
+	AttachedNode            string `json:"attached_node"`
+	AttachStatus            string `json:"attach_status"`
+	AttachError             string `json:"attach_error"`
+	Age                     string `json:"age"`
+	Message                 string `json:"message"`
+	Cluster                 string `json:"cluster"`
 }
 
-func supportedColumnPair(volume k8s.VolumeInfo) map[string]string {
-	return map[string]string{
-		"Namespace":      volume.Namespace,
-		"Protocol":       volume.Protocol,
-		"Status":         volume.PersistentVolumeStatus,
-		"CSI Driver":     volume.Driver,
-		"Storage Pool":   volume.StoragePoolName,
-		"Storage System": volume.StorageSystem,
-		"Storage Class":  volume.StorageClass,
+func generateVolumeTableJSON(volumes []k8s.VolumeInfo, filter rowFilter) []Table {
+	table := make([]Table, 0)
+
+	for _, volume := range volumes {
+		row := newTableRow(volume)
+		if filter.match(volumeRow(row)) {
+			table = append(table, row)
+		}
 	}
+
+	return table
 }
 
-func canAddRow(volume k8s.VolumeInfo, lookUp []map[string]string) bool {
-	canADD := true
-	filter := supportedColumnPair(volume)
-	for _, look := range lookUp {
-		for key, v := range look {
-			val, ok := filter[key]
-			canADD = canADD && ok && strings.Contains(v, val) // all keys must match
-		}
+// newTableRow returns the volume table row of a volume
+func newTableRow(volume k8s.VolumeInfo) Table {
+	return Table{
+		Namespace:               volume.Namespace,
+		PersistentVolume:        volume.PersistentVolume,
+		PersistentVolumeClaim:   volume.VolumeClaimName,
+		CSIDriver:               volume.Driver,
+		Created:                 volume.CreatedTime,
+		ProvisionedSize:         volume.ProvisionedSize,
+		StorageClass:            volume.StorageClass,
+		StorageSystemVolumeName: volume.StorageSystemVolumeName,
+		StoragePool:             volume.StoragePoolName,
+		StorageSystem:           volume.StorageSystem,
+		Protocol:                volume.Protocol,
+		Status:                  volume.PersistentVolumeStatus,
+		Pod:                     joinPods(volume.Pods, func(p k8s.PodInfo) string { return p.Name }),
+		OwnerKind:               joinPods(volume.Pods, func(p k8s.PodInfo) string { return p.OwnerKind }),
+		Owner:                   joinPods(volume.Pods, func(p k8s.PodInfo) string { return p.OwnerName }),
+		Node:                    joinPods(volume.Pods, func(p k8s.PodInfo) string { return p.Node }),
+		AttachedNode:            volume.AttachedNode,
+		AttachStatus:            volume.AttachStatus,
+		AttachError:             volume.AttachError,
+		Age:                     age(volume.CreatedTime),
+		Message:                 volume.Message,
+		Cluster:                 volume.Cluster,
 	}
-	return canADD
 }
 
-func generateVolumeTableJSON(volumes []k8s.VolumeInfo, lookUp []map[string]string) []Table {
-	table := make([]Table, 0)
+// age returns the time elapsed since a volume was created, formatted like kubectl; empty when the time is unknown
+func age(created string) string {
+	t, ok := parseTime(created)
+	if !ok {
+		return ""
+	}
+	return duration.HumanDuration(time.Since(t))
+}
+
+// volumeTable returns the volume topology table
+func (s *Service) volumeTable() topologyTable {
+	key, _ := volumeSchema.column("persistent_volume")
+	return topologyTable{
+		name:   "volumes",
+		item:   "persistent volume",
+		file:   "topology",
+		schema: volumeSchema,
+		key:    key,
+		rows: func(ctx context.Context) ([][]string, error) {
+			volumes, err := s.VolumeFinder.GetPersistentVolumes(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return volumeRows(volumes), nil
+		},
+		// unbound claims have no volume yet, so they are neither counted nor provisioned
+		aggregated: columnFilter{index: key, predicate: func(v string) bool { return v != "" }},
+	}
+}
 
+// volumeRows returns the rows of the volumes, in the same order
+func volumeRows(volumes []k8s.VolumeInfo) [][]string {
+	rows := make([][]string, 0, len(volumes))
 	for _, volume := range volumes {
-		if canAddRow(volume, lookUp) {
-			table = append(table, Table{
-				Namespace:               volume.Namespace,
-				PersistentVolume:        volume.PersistentVolume,
-				PersistentVolumeClaim:   volume.VolumeClaimName,
-				CSIDriver:               volume.Driver,
-				Created:                 volume.CreatedTime,
-				ProvisionedSize:         volume.ProvisionedSize,
-				StorageClass:            volume.StorageClass,
-				StorageSystemVolumeName: volume.StorageSystemVolumeName,
-				StoragePool:             volume.StoragePoolName,
-				StorageSystem:           volume.StorageSystem,
-				Protocol:                volume.Protocol,
-				Status:                  volume.PersistentVolumeStatus,
-			})
+		rows = append(rows, volumeRow(newTableRow(volume)))
+	}
+	return rows
+}
+
+// joinPods returns the distinct non-empty values of a pod field, comma separated, for volumes mounted by several pods
+func joinPods(pods []k8s.PodInfo, field func(k8s.PodInfo) string) string {
+	return strings.Join(podValues(pods, field), ", ")
+}
+
+// podValues returns the distinct non-empty values of a pod field
+func podValues(pods []k8s.PodInfo, field func(k8s.PodInfo) string) []string {
+	values := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		if value := field(pod); value != "" && !k8s.Contains(values, value) {
+			values = append(values, value)
 		}
 	}
+	return values
+}
 
-	return table
+// queryTarget is a target of a Grafana query
+type queryTarget struct {
+	Target string `json:"target"`
+	RefID  string `json:"refId"`
+	Type   string `json:"type"`
+	Hide   bool   `json:"hide"`
+}
+
+// response returns the Grafana responses for the target's rows, honouring the target's type:
+// timeserie targets get the row count as a time series and other targets, including untyped ones, get a typed table
+// frame. Aggregation targets get the aggregated table, or one time series per group and aggregate.
+func (target queryTarget) response(table topologyTable, query targetQuery, rows [][]string) []interface{} {
+	if query.aggregation != nil {
+		if table.aggregated != nil {
+			rows = filterRows(rows, table.aggregated)
+		}
+		schema, aggregated := table.schema.aggregate(query.aggregation, rows)
+		if target.Type != targetTimeSeries {
+			return []interface{}{schema.frame(target.RefID, aggregated)}
+		}
+		return query.aggregation.series(target.RefID, table.name, schema, aggregated)
+	}
+
+	if target.Type != targetTimeSeries {
+		return []interface{}{table.schema.frame(target.RefID, rows)}
+	}
+	return []interface{}{timeSeries{
+		RefID:      target.RefID,
+		Target:     table.name,
+		Datapoints: [][]float64{{float64(len(rows)), float64(time.Now().UnixMilli())}},
+	}}
 }
 
 // GetSecuredCipherSuites returns a set of secure cipher suites.
@@ -94,6 +94,30 @@ func TestRootHandler(t *testing.T) {
 	}
 }
 
+func TestReadyHandler(t *testing.T) {
+	tests := map[string]struct {
+		ready          bool
+		expectedStatus int
+	}{
+		"ready":     {ready: true, expectedStatus: http.StatusOK},
+		"not ready": {ready: false, expectedStatus: http.StatusServiceUnavailable},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().Ready().Times(1).Return(tc.ready)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			res, err := http.Get(ctx.server.URL + "/ready")
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
 func TestQueryHandler(t *testing.T) {
 	type checkFn func(*testing.T, []byte, int, error)
 	check := func(fns ...checkFn) []checkFn { return fns }
@@ -154,6 +178,14 @@ func TestQueryHandler(t *testing.T) {
 
 			return volumeFinder, testOverrides{}, check(hasExpectedStatusCode(http.StatusInternalServerError)), nil
 		},
+		"cache not synced": func(*testing.T) (service.VolumeInfoGetter, testOverrides, []checkFn, io.Reader) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(nil, k8s.ErrCacheNotSynced)
+
+			return volumeFinder, testOverrides{}, check(hasExpectedStatusCode(http.StatusServiceUnavailable)), nil
+		},
 		"error marshalling response": func(*testing.T) (service.VolumeInfoGetter, testOverrides, []checkFn, io.Reader) {
 			ctrl := gomock.NewController(t)
 			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
@@ -207,7 +239,7 @@ func TestQueryHandler(t *testing.T) {
 
 			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(volumeInfo, nil)
 
-			return volumeFinder, patch, check(hasExpectedStatusCode(http.StatusInternalServerError)), bytes.NewBuffer([]byte(testJSON))
+			return volumeFinder, patch, check(hasExpectedStatusCode(http.StatusBadRequest)), bytes.NewBuffer([]byte(testJSON))
 		},
 		"error writing http": func(*testing.T) (service.VolumeInfoGetter, testOverrides, []checkFn, io.Reader) {
 			ctrl := gomock.NewController(t)
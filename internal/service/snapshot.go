@@ -0,0 +1,119 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+)
+
+// snapshotSchema describes the columns of the snapshot topology table
+var snapshotSchema = tableSchema{
+	{Key: "namespace", Text: "Namespace", Type: columnString},
+	{Key: "volume_snapshot", Text: "Volume Snapshot", Type: columnString},
+	{Key: "volume_snapshot_content", Text: "Volume Snapshot Content", Type: columnString},
+	{Key: "volume_snapshot_class", Text: "Volume Snapshot Class", Type: columnString},
+	{Key: "status", Text: "Status", Type: columnString},
+	{Key: "csi_driver", Text: "CSI Driver", Type: columnString},
+	{Key: "created", Text: "Created", Type: columnTime},
+	{Key: "restore_size", Text: "Restore Size", Type: columnNumber},
+	{Key: "snapshot_handle", Text: "Snapshot Handle", Type: columnString},
+	{Key: "source_persistent_volume_claim", Text: "Source Persistent Volume Claim", Type: columnString},
+	{Key: "source_persistent_volume", Text: "Source Persistent Volume", Type: columnString},
+	{Key: "storage_system", Text: "Storage System", Type: columnString},
+	{Key: "deletion_policy", Text: "Deletion Policy", Type: columnString},
+	{Key: "error", Text: "Error", Type: columnString},
+	{Key: "cluster", Text: "Cluster", Type: columnString},
+}
+
+// snapshotRow returns the raw values of a snapshot in snapshotSchema order
+func snapshotRow(snapshot k8s.SnapshotInfo) []string {
+	return []string{
+		snapshot.Namespace,
+		snapshot.VolumeSnapshot,
+		snapshot.VolumeSnapshotContent,
+		snapshot.VolumeSnapshotClass,
+		snapshot.Status,
+		snapshot.Driver,
+		snapshot.CreatedTime,
+		snapshot.RestoreSize,
+		snapshot.SnapshotHandle,
+		snapshot.SourcePersistentVolumeClaim,
+		snapshot.SourcePersistentVolume,
+		snapshot.StorageSystem,
+		snapshot.DeletionPolicy,
+		snapshot.Error,
+		snapshot.Cluster,
+	}
+}
+
+// snapshotTable returns the snapshot topology table, identified by volume snapshot content name
+func (s *Service) snapshotTable() topologyTable {
+	key, _ := snapshotSchema.column("volume_snapshot_content")
+	return topologyTable{
+		name:   "snapshots",
+		item:   "volume snapshot content",
+		file:   "snapshots",
+		schema: snapshotSchema,
+		key:    key,
+		rows: func(ctx context.Context) ([][]string, error) {
+			snapshots, err := s.VolumeFinder.GetVolumeSnapshots(ctx)
+			if err != nil {
+				return nil, err
+			}
+			rows := make([][]string, 0, len(snapshots))
+			for _, snapshot := range snapshots {
+				rows = append(rows, snapshotRow(snapshot))
+			}
+			return rows, nil
+		},
+	}
+}
+
+// listSnapshotsRequest returns the snapshots matching the query string filters
+func (s *Service) listSnapshotsRequest(w http.ResponseWriter, r *http.Request) {
+	table := s.snapshotTable()
+	listTable(s, w, r, table, table.schema.rowItem)
+}
+
+// getSnapshotRequest returns the snapshot with the volume snapshot content name given in the path
+func (s *Service) getSnapshotRequest(w http.ResponseWriter, r *http.Request) {
+	table := s.snapshotTable()
+	getTableRow(s, w, r, table, table.schema.rowItem)
+}
+
+// snapshotQueryRequest answers Grafana queries on the snapshot table
+func (s *Service) snapshotQueryRequest(w http.ResponseWriter, r *http.Request) {
+	s.queryTable(w, r, s.snapshotTable())
+}
+
+// snapshotSearchRequest returns the names of the snapshot columns that can be used in target filters
+func (s *Service) snapshotSearchRequest(w http.ResponseWriter, r *http.Request) {
+	s.search(w, r, snapshotSchema)
+}
+
+// snapshotTagKeysRequest returns the snapshot columns that can be used as Grafana ad-hoc filter keys
+func (s *Service) snapshotTagKeysRequest(w http.ResponseWriter, _ *http.Request) {
+	s.tagKeys(w, snapshotSchema)
+}
+
+// snapshotTagValuesRequest returns the distinct values currently present for the requested snapshot column
+func (s *Service) snapshotTagValuesRequest(w http.ResponseWriter, r *http.Request) {
+	s.tagValues(w, r, s.snapshotTable())
+}
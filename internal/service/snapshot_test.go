@@ -0,0 +1,245 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSnapshots() []k8s.SnapshotInfo {
+	t1, _ := time.Parse(time.RFC3339, "2020-09-01T10:00:00Z")
+	t2, _ := time.Parse(time.RFC3339, "2020-09-02T10:00:00Z")
+	return []k8s.SnapshotInfo{
+		{
+			Namespace:                   "ns-1",
+			VolumeSnapshot:              "snap-1",
+			VolumeSnapshotContent:       "snapcontent-1",
+			VolumeSnapshotClass:         "powerstore-snapclass",
+			Status:                      k8s.SnapshotStatusReady,
+			Driver:                      "csi-powerstore.dellemc.com",
+			RestoreSize:                 "8Gi",
+			SnapshotHandle:              "snap-handle-1/10.0.0.1/scsi",
+			SourcePersistentVolumeClaim: "pvc-1",
+			SourcePersistentVolume:      "pv-1",
+			StorageSystem:               "10.0.0.1",
+			DeletionPolicy:              "Delete",
+			CreatedTime:                 t1.String(),
+		},
+		{
+			Namespace:                   "ns-2",
+			VolumeSnapshot:              "snap-2",
+			VolumeSnapshotContent:       "snapcontent-2",
+			VolumeSnapshotClass:         "powerflex-snapclass",
+			Status:                      k8s.SnapshotStatusError,
+			Driver:                      "csi-vxflexos.dellemc.com",
+			SourcePersistentVolumeClaim: "pvc-2",
+			SourcePersistentVolume:      "pv-2",
+			StorageSystem:               "storage-system-id",
+			DeletionPolicy:              "Retain",
+			CreatedTime:                 t2.String(),
+			Error:                       "snapshot creation failed",
+		},
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	tests := map[string]struct {
+		query             string
+		expectedStatus    int
+		expectedSnapshots []string
+	}{
+		"all snapshots": {
+			query:             "",
+			expectedStatus:    http.StatusOK,
+			expectedSnapshots: []string{"snapcontent-1", "snapcontent-2"},
+		},
+		"filtered by status": {
+			query:             "?status=Error",
+			expectedStatus:    http.StatusOK,
+			expectedSnapshots: []string{"snapcontent-2"},
+		},
+		"filtered by source volume": {
+			query:             "?source_persistent_volume=%3D~pv-%5B1%5D",
+			expectedStatus:    http.StatusOK,
+			expectedSnapshots: []string{"snapcontent-1"},
+		},
+		"sorted descending by created": {
+			query:             "?sort=-created",
+			expectedStatus:    http.StatusOK,
+			expectedSnapshots: []string{"snapcontent-2", "snapcontent-1"},
+		},
+		"volume column": {
+			query:          "?persistent_volume=pv-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetVolumeSnapshots(gomock.Any()).AnyTimes().Return(testSnapshots(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := get(t, ctx.server.URL+"/api/v1/snapshots"+tc.query)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var list testList
+			assert.Nil(t, json.Unmarshal(body, &list))
+			snapshots := make([]string, 0)
+			for _, item := range list.Items {
+				snapshots = append(snapshots, item["volume_snapshot_content"].(string))
+			}
+			assert.Equal(t, tc.expectedSnapshots, snapshots)
+		})
+	}
+}
+
+func TestGetSnapshot(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		expectedStatus int
+		expected       map[string]interface{}
+	}{
+		"found": {
+			path:           "/api/v1/snapshots/snapcontent-1?fields=volume_snapshot,restore_size,created",
+			expectedStatus: http.StatusOK,
+			expected: map[string]interface{}{
+				"volume_snapshot": "snap-1",
+				"restore_size":    float64(8589934592),
+				"created":         "2020-09-01T10:00:00Z",
+			},
+		},
+		"not found": {
+			path:           "/api/v1/snapshots/snapcontent-3",
+			expectedStatus: http.StatusNotFound,
+			expected:       map[string]interface{}{"message": "volume snapshot content \"snapcontent-3\" not found"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetVolumeSnapshots(gomock.Any()).Times(1).Return(testSnapshots(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := get(t, ctx.server.URL+tc.path)
+			assert.Equal(t, tc.expectedStatus, status)
+			var result map[string]interface{}
+			assert.Nil(t, json.Unmarshal(body, &result))
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestSnapshotQuery(t *testing.T) {
+	tests := map[string]struct {
+		body           string
+		expectedStatus int
+		expectedRows   int
+	}{
+		"all snapshots": {
+			body:           `{"targets": [{"target": "{}", "refId": "A", "type": "table"}]}`,
+			expectedStatus: http.StatusOK,
+			expectedRows:   2,
+		},
+		"target filter": {
+			body:           `{"targets": [{"target": "{\"csi_driver\": \"csi-powerstore.dellemc.com\"}", "refId": "A", "type": "table"}]}`,
+			expectedStatus: http.StatusOK,
+			expectedRows:   1,
+		},
+		"ad-hoc filter": {
+			body: `{"targets": [{"target": "{}", "refId": "A", "type": "table"}],
+				"adhocFilters": [{"key": "Deletion Policy", "operator": "=", "value": "Retain"}]}`,
+			expectedStatus: http.StatusOK,
+			expectedRows:   1,
+		},
+		"unknown column": {
+			body:           `{"targets": [{"target": "{\"storage_pool\": \"pool\"}", "refId": "A", "type": "table"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetVolumeSnapshots(gomock.Any()).Times(1).Return(testSnapshots(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := post(t, ctx.server.URL+"/snapshots/query", tc.body)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var frames []testFrame
+			assert.Nil(t, json.Unmarshal(body, &frames))
+			assert.Equal(t, 1, len(frames))
+			assert.Equal(t, "Volume Snapshot Content", frames[0].Columns[2].Text)
+			assert.Equal(t, tc.expectedRows, len(frames[0].Rows))
+		})
+	}
+}
+
+func TestSnapshotTagValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetVolumeSnapshots(gomock.Any()).Times(1).Return(testSnapshots(), nil)
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	status, body := post(t, ctx.server.URL+"/snapshots/tag-values", `{"key": "Status"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `[{"text": "Error"}, {"text": "Ready"}]`, string(body))
+
+	status, body = post(t, ctx.server.URL+"/snapshots/search", `{"target": "source"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `["Source Persistent Volume Claim", "Source Persistent Volume"]`, string(body))
+}
+
+func TestSnapshotsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetVolumeSnapshots(gomock.Any()).Times(2).Return(nil, errors.New("error"))
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	status, _ := get(t, ctx.server.URL+"/api/v1/snapshots")
+	assert.Equal(t, http.StatusInternalServerError, status)
+	status, _ = post(t, ctx.server.URL+"/snapshots/query", `{}`)
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
@@ -0,0 +1,217 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	tracer "github.com/dell/karavi-topology/internal/tracers"
+	"github.com/gorilla/mux"
+)
+
+// storageSystemsResponse is the storage-system-centric view of the volumes: the Kubernetes consumers of every storage
+// system and pool
+type storageSystemsResponse struct {
+	StorageSystems []*storageSystem `json:"storage_systems"`
+	Totals         storageTotals    `json:"totals"`
+}
+
+// storageTotals counts the volumes of a group and sums their provisioned size in bytes
+type storageTotals struct {
+	Volumes         int   `json:"volumes"`
+	ProvisionedSize int64 `json:"provisioned_size"`
+}
+
+// storageSystem is a storage system and the volumes of its pools
+type storageSystem struct {
+	StorageSystem string         `json:"storage_system"`
+	CSIDrivers    []string       `json:"csi_drivers"`
+	StoragePools  []*storagePool `json:"storage_pools"`
+	Totals        storageTotals  `json:"totals"`
+}
+
+// storagePool is a storage pool and its volumes
+type storagePool struct {
+	StoragePool string          `json:"storage_pool"`
+	Volumes     []storageVolume `json:"volumes"`
+	Totals      storageTotals   `json:"totals"`
+}
+
+// storageVolume is a volume of a storage pool and the Kubernetes objects consuming it
+type storageVolume struct {
+	Cluster                 string   `json:"cluster,omitempty"`
+	StorageSystemVolumeName string   `json:"storage_system_volume_name"`
+	PersistentVolume        string   `json:"persistent_volume"`
+	Namespace               string   `json:"namespace"`
+	PersistentVolumeClaim   string   `json:"persistent_volume_claim"`
+	Pods                    []string `json:"pods"`
+	Nodes                   []string `json:"nodes"`
+	Status                  string   `json:"status"`
+	Protocol                string   `json:"protocol"`
+	// ProvisionedSize is in bytes; nil when the size cannot be parsed
+	ProvisionedSize *int64 `json:"provisioned_size"`
+}
+
+// listStorageSystemsRequest returns the volumes matching the query string filters grouped by storage system and pool
+func (s *Service) listStorageSystemsRequest(w http.ResponseWriter, r *http.Request) {
+	response, ok := s.storageSystems(w, r)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, response)
+}
+
+// getStorageSystemRequest returns the volumes of the storage system given in the path grouped by pool
+func (s *Service) getStorageSystemRequest(w http.ResponseWriter, r *http.Request) {
+	response, ok := s.storageSystems(w, r)
+	if !ok {
+		return
+	}
+	name := mux.Vars(r)["name"]
+	for _, system := range response.StorageSystems {
+		if system.StorageSystem == name {
+			s.writeJSON(w, system)
+			return
+		}
+	}
+	s.writeError(w, http.StatusNotFound, fmt.Errorf("storage system %q not found", name))
+}
+
+// storageSystems groups the volumes matching the query string filters; the error response is written when it fails.
+// The systems are not a list of rows, so the list parameters are rejected rather than ignored.
+func (s *Service) storageSystems(w http.ResponseWriter, r *http.Request) (storageSystemsResponse, bool) {
+	ctx, span := tracer.GetTracer(context.Background(), "storageSystemsRequest")
+	defer span.End()
+
+	values := r.URL.Query()
+	for _, param := range []string{sortParam, limitParam, continueParam, fieldsParam, formatParam} {
+		if values.Has(param) {
+			err := fmt.Errorf("%s is not supported by storage systems", param)
+			s.writeError(w, http.StatusBadRequest, err)
+			s.Logger.WithError(err).Error("parsing storage systems query")
+			return storageSystemsResponse{}, false
+		}
+	}
+	filter, err := volumeSchema.parseFilters(values)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		s.Logger.WithError(err).Error("parsing storage systems query")
+		return storageSystemsResponse{}, false
+	}
+
+	volumes, err := s.VolumeFinder.GetPersistentVolumes(ctx)
+	if err != nil {
+		s.writeError(w, errorStatus(err), fmt.Errorf("getting volumes"))
+		s.Logger.WithError(err).Error("getting persistent volumes")
+		return storageSystemsResponse{}, false
+	}
+
+	matching := make([]k8s.VolumeInfo, 0, len(volumes))
+	for _, volume := range volumes {
+		// unbound claims have no volume on a storage system yet
+		if volume.PersistentVolume != "" && filter.match(volumeRow(newTableRow(volume))) {
+			matching = append(matching, volume)
+		}
+	}
+	return groupStorageSystems(matching), true
+}
+
+// groupStorageSystems groups volumes by storage system and pool, sorted by name, and totals every group
+func groupStorageSystems(volumes []k8s.VolumeInfo) storageSystemsResponse {
+	response := storageSystemsResponse{StorageSystems: make([]*storageSystem, 0)}
+	systems := make(map[string]*storageSystem)
+	pools := make(map[[2]string]*storagePool)
+	for _, volume := range volumes {
+		system, ok := systems[volume.StorageSystem]
+		if !ok {
+			system = &storageSystem{StorageSystem: volume.StorageSystem, CSIDrivers: make([]string, 0), StoragePools: make([]*storagePool, 0)}
+			systems[volume.StorageSystem] = system
+			response.StorageSystems = append(response.StorageSystems, system)
+		}
+		if volume.Driver != "" && !k8s.Contains(system.CSIDrivers, volume.Driver) {
+			system.CSIDrivers = append(system.CSIDrivers, volume.Driver)
+		}
+
+		key := [2]string{volume.StorageSystem, volume.StoragePoolName}
+		pool, ok := pools[key]
+		if !ok {
+			pool = &storagePool{StoragePool: volume.StoragePoolName, Volumes: make([]storageVolume, 0)}
+			pools[key] = pool
+			system.StoragePools = append(system.StoragePools, pool)
+		}
+
+		item := newStorageVolume(volume)
+		pool.Volumes = append(pool.Volumes, item)
+		for _, totals := range []*storageTotals{&response.Totals, &system.Totals, &pool.Totals} {
+			totals.add(item)
+		}
+	}
+
+	sort.Slice(response.StorageSystems, func(i, j int) bool {
+		return response.StorageSystems[i].StorageSystem < response.StorageSystems[j].StorageSystem
+	})
+	for _, system := range response.StorageSystems {
+		sort.Strings(system.CSIDrivers)
+		sort.Slice(system.StoragePools, func(i, j int) bool {
+			return system.StoragePools[i].StoragePool < system.StoragePools[j].StoragePool
+		})
+		for _, pool := range system.StoragePools {
+			sort.Slice(pool.Volumes, func(i, j int) bool { return pool.Volumes[i].less(pool.Volumes[j]) })
+		}
+	}
+	return response
+}
+
+// newStorageVolume returns the storage view of a volume
+func newStorageVolume(volume k8s.VolumeInfo) storageVolume {
+	item := storageVolume{
+		Cluster:                 volume.Cluster,
+		StorageSystemVolumeName: volume.StorageSystemVolumeName,
+		PersistentVolume:        volume.PersistentVolume,
+		Namespace:               volume.Namespace,
+		PersistentVolumeClaim:   volume.VolumeClaimName,
+		Pods:                    podValues(volume.Pods, func(p k8s.PodInfo) string { return p.Name }),
+		Nodes:                   podValues(volume.Pods, func(p k8s.PodInfo) string { return p.Node }),
+		Status:                  volume.PersistentVolumeStatus,
+		Protocol:                volume.Protocol,
+	}
+	if size, ok := parseBytes(volume.ProvisionedSize); ok {
+		item.ProvisionedSize = &size
+	}
+	return item
+}
+
+// add counts a volume in the totals
+func (t *storageTotals) add(volume storageVolume) {
+	t.Volumes++
+	if volume.ProvisionedSize != nil {
+		t.ProvisionedSize += *volume.ProvisionedSize
+	}
+}
+
+// less orders volumes by namespace, claim, persistent volume and cluster
+func (v storageVolume) less(other storageVolume) bool {
+	return slices.Compare(
+		[]string{v.Namespace, v.PersistentVolumeClaim, v.PersistentVolume, v.Cluster},
+		[]string{other.Namespace, other.PersistentVolumeClaim, other.PersistentVolume, other.Cluster},
+	) < 0
+}
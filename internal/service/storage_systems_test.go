@@ -0,0 +1,166 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func storageVolumes() []k8s.VolumeInfo {
+	return append(testVolumes(),
+		k8s.VolumeInfo{
+			Namespace:               "ns-1",
+			VolumeClaimName:         "pvc-3",
+			PersistentVolume:        "pv-3",
+			PersistentVolumeStatus:  "Bound",
+			Driver:                  "csi-vxflexos.dellemc.com",
+			ProvisionedSize:         "8Gi",
+			StorageSystemVolumeName: "k8s-pv-3",
+			StoragePoolName:         "pool-1",
+			StorageSystem:           "7045c4cc20dffc0f",
+			Protocol:                "scsi",
+			Pods:                    []k8s.PodInfo{{Name: "db-0", Node: "node-3"}},
+			Cluster:                 "east",
+		},
+		k8s.VolumeInfo{
+			Namespace:               "ns-2",
+			VolumeClaimName:         "pvc-4",
+			PersistentVolume:        "pv-4",
+			PersistentVolumeStatus:  "Bound",
+			Driver:                  "csi-vxflexos.dellemc.com",
+			ProvisionedSize:         "unknown",
+			StorageSystemVolumeName: "k8s-pv-4",
+			StoragePoolName:         "pool-2",
+			StorageSystem:           "7045c4cc20dffc0f",
+			Protocol:                "scsi",
+		},
+		k8s.VolumeInfo{Namespace: "ns-2", VolumeClaimName: "pending", PersistentVolumeStatus: "Pending"},
+	)
+}
+
+func TestStorageSystems(t *testing.T) {
+	pv1 := `{"storage_system_volume_name": "pv-1", "persistent_volume": "pv-1", "namespace": "ns-1",
+		"persistent_volume_claim": "pvc-1", "pods": ["web-0", "web-1"], "nodes": ["node-1", "node-2"], "status": "Bound",
+		"protocol": "scsi", "provisioned_size": 8589934592}`
+	pv2 := `{"storage_system_volume_name": "k8s-pv-2", "persistent_volume": "pv-2", "namespace": "ns-2",
+		"persistent_volume_claim": "pvc-2", "pods": [], "nodes": [], "status": "Released", "protocol": "scsi",
+		"provisioned_size": 17179869184}`
+	pv3 := `{"cluster": "east", "storage_system_volume_name": "k8s-pv-3", "persistent_volume": "pv-3", "namespace": "ns-1",
+		"persistent_volume_claim": "pvc-3", "pods": ["db-0"], "nodes": ["node-3"], "status": "Bound", "protocol": "scsi",
+		"provisioned_size": 8589934592}`
+	pv4 := `{"storage_system_volume_name": "k8s-pv-4", "persistent_volume": "pv-4", "namespace": "ns-2",
+		"persistent_volume_claim": "pvc-4", "pods": [], "nodes": [], "status": "Bound", "protocol": "scsi",
+		"provisioned_size": null}`
+	powerstore := `{"storage_system": "10.0.0.1", "csi_drivers": ["csi-powerstore.dellemc.com"],
+		"totals": {"volumes": 1, "provisioned_size": 8589934592}, "storage_pools": [
+			{"storage_pool": "N/A", "totals": {"volumes": 1, "provisioned_size": 8589934592}, "volumes": [` + pv1 + `]}]}`
+	powerflex := `{"storage_system": "7045c4cc20dffc0f", "csi_drivers": ["csi-vxflexos.dellemc.com"],
+		"totals": {"volumes": 3, "provisioned_size": 25769803776}, "storage_pools": [
+			{"storage_pool": "pool-1", "totals": {"volumes": 2, "provisioned_size": 25769803776}, "volumes": [` + pv3 + `,` + pv2 + `]},
+			{"storage_pool": "pool-2", "totals": {"volumes": 1, "provisioned_size": 0}, "volumes": [` + pv4 + `]}]}`
+
+	tests := map[string]struct {
+		path           string
+		expectedStatus int
+		expected       string
+	}{
+		"all storage systems": {
+			path:           "/api/v1/storage-systems",
+			expectedStatus: http.StatusOK,
+			expected: `{"totals": {"volumes": 4, "provisioned_size": 34359738368},
+				"storage_systems": [` + powerstore + `,` + powerflex + `]}`,
+		},
+		"filtered": {
+			path:           "/api/v1/storage-systems?namespace=ns-2&storage_pool=pool-1",
+			expectedStatus: http.StatusOK,
+			expected: `{"totals": {"volumes": 1, "provisioned_size": 17179869184}, "storage_systems": [
+				{"storage_system": "7045c4cc20dffc0f", "csi_drivers": ["csi-vxflexos.dellemc.com"],
+				"totals": {"volumes": 1, "provisioned_size": 17179869184}, "storage_pools": [
+					{"storage_pool": "pool-1", "totals": {"volumes": 1, "provisioned_size": 17179869184}, "volumes": [` + pv2 + `]}]}]}`,
+		},
+		"no match": {
+			path:           "/api/v1/storage-systems?cluster=west",
+			expectedStatus: http.StatusOK,
+			expected:       `{"totals": {"volumes": 0, "provisioned_size": 0}, "storage_systems": []}`,
+		},
+		"unknown filter": {
+			path:           "/api/v1/storage-systems?color=blue",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"sort is rejected": {
+			path:           "/api/v1/storage-systems?sort=namespace",
+			expectedStatus: http.StatusBadRequest,
+			expected:       `{"message": "sort is not supported by storage systems"}`,
+		},
+		"limit is rejected": {
+			path:           "/api/v1/storage-systems?namespace=ns-1&limit=1",
+			expectedStatus: http.StatusBadRequest,
+			expected:       `{"message": "limit is not supported by storage systems"}`,
+		},
+		"fields are rejected on a single system": {
+			path:           "/api/v1/storage-systems/7045c4cc20dffc0f?fields=namespace",
+			expectedStatus: http.StatusBadRequest,
+			expected:       `{"message": "fields is not supported by storage systems"}`,
+		},
+		"storage system": {
+			path:           "/api/v1/storage-systems/7045c4cc20dffc0f",
+			expectedStatus: http.StatusOK,
+			expected:       powerflex,
+		},
+		"storage system not found": {
+			path:           "/api/v1/storage-systems/10.0.0.1?namespace=ns-2",
+			expectedStatus: http.StatusNotFound,
+			expected:       `{"message": "storage system \"10.0.0.1\" not found"}`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+			volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).AnyTimes().Return(storageVolumes(), nil)
+
+			ctx, teardown := setup(volumeFinder)
+			defer teardown()
+
+			status, body := get(t, ctx.server.URL+tc.path)
+			assert.Equal(t, tc.expectedStatus, status)
+			if tc.expected != "" {
+				assert.JSONEq(t, tc.expected, string(body))
+			}
+		})
+	}
+}
+
+func TestStorageSystemsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(nil, errors.New("error"))
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	status, _ := get(t, ctx.server.URL+"/api/v1/storage-systems")
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
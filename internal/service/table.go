@@ -0,0 +1,250 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Column types understood by Grafana
+const (
+	columnString = "string"
+	columnNumber = "number"
+	columnTime   = "time"
+)
+
+// Target types sent by Grafana
+const (
+	targetTable      = "table"
+	targetTimeSeries = "timeserie"
+)
+
+// tableColumn describes a column of a topology table
+type tableColumn struct {
+	Key  string
+	Text string
+	Type string
+}
+
+// tableSchema is the ordered list of columns of a topology table; rows hold the raw column values in the same order
+type tableSchema []tableColumn
+
+// topologyTable is a table served by the Grafana and REST API endpoints
+type topologyTable struct {
+	// name names the rows in time series, e.g. "volumes"
+	name string
+	// item names a single row in errors, e.g. "persistent volume"
+	item string
+	// file is the name of exported files, without extension
+	file   string
+	schema tableSchema
+	// key is the index of the column identifying a row in REST API paths
+	key int
+	// rows returns the raw rows of the table
+	rows func(ctx context.Context) ([][]string, error)
+	// aggregated selects the rows that aggregation targets summarize; every row is aggregated when nil
+	aggregated rowFilter
+	// params are the parameters of list requests that select the rows without being filters, e.g. the since of events
+	params []string
+	// anchor is carried by the continue tokens of list requests, see listQuery
+	anchor string
+}
+
+// volumeSchema describes the columns of the volume topology table
+var volumeSchema = tableSchema{
+	{Key: "namespace", Text: "Namespace", Type: columnString},
+	{Key: "persistent_volume", Text: "Persistent Volume", Type: columnString},
+	{Key: "status", Text: "Status", Type: columnString},
+	{Key: "persistent_volume_claim", Text: "Persistent Volume Claim", Type: columnString},
+	{Key: "csi_driver", Text: "CSI Driver", Type: columnString},
+	{Key: "created", Text: "Created", Type: columnTime},
+	{Key: "provisioned_size", Text: "Provisioned Size", Type: columnNumber},
+	{Key: "storage_class", Text: "Storage Class", Type: columnString},
+	{Key: "storage_system_volume_name", Text: "Storage System Volume Name", Type: columnString},
+	{Key: "storage_pool", Text: "Storage Pool", Type: columnString},
+	{Key: "storage_system", Text: "Storage System", Type: columnString},
+	{Key: "protocol", Text: "Protocol", Type: columnString},
+	{Key: "pod", Text: "Pod", Type: columnString},
+	{Key: "owner_kind", Text: "Owner Kind", Type: columnString},
+	{Key: "owner", Text: "Owner", Type: columnString},
+	{Key: "node", Text: "Node", Type: columnString},
+	{Key: "attached_node", Text: "Attached Node", Type: columnString},
+	{Key: "attach_status", Text: "Attach Status", Type: columnString},
+	{Key: "attach_error", Text: "Attach Error", Type: columnString},
+	{Key: "age", Text: "Age", Type: columnString},
+	{Key: "message", Text: "Message", Type: columnString},
+	{Key: "cluster", Text: "Cluster", Type: columnString},
+}
+
+// volumeRow returns the raw values of a table row in volumeSchema order
+func volumeRow(t Table) []string {
+	return []string{
+		t.Namespace,
+		t.PersistentVolume,
+		t.Status,
+		t.PersistentVolumeClaim,
+		t.CSIDriver,
+		t.Created,
+		t.ProvisionedSize,
+		t.StorageClass,
+		t.StorageSystemVolumeName,
+		t.StoragePool,
+		t.StorageSystem,
+		t.Protocol,
+		t.Pod,
+		t.OwnerKind,
+		t.Owner,
+		t.Node,
+		t.AttachedNode,
+		t.AttachStatus,
+		t.AttachError,
+		t.Age,
+		t.Message,
+		t.Cluster,
+	}
+}
+
+// filterRows returns the rows matching the filter
+func filterRows(rows [][]string, filter rowFilter) [][]string {
+	filtered := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		if filter.match(row) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+// rawObject is a row marshalled as a JSON object of its raw values, keyed by column name in schema order
+type rawObject struct {
+	schema tableSchema
+	row    []string
+}
+
+// MarshalJSON implements json.Marshaler
+func (o rawObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range o.schema {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(column.Key)
+		value, _ := json.Marshal(o.row[i])
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// objects returns the rows as JSON objects of their raw values
+func (schema tableSchema) objects(rows [][]string) []rawObject {
+	objects := make([]rawObject, 0, len(rows))
+	for _, row := range rows {
+		objects = append(objects, rawObject{schema: schema, row: row})
+	}
+	return objects
+}
+
+// frameColumn is a column header of a Grafana table response
+type frameColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// tableFrame is a Grafana table response for a single target
+type tableFrame struct {
+	RefID   string          `json:"refId,omitempty"`
+	Columns []frameColumn   `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+	Type    string          `json:"type"`
+}
+
+// timeSeries is a Grafana time series response for a single target
+type timeSeries struct {
+	RefID      string      `json:"refId,omitempty"`
+	Target     string      `json:"target"`
+	Datapoints [][]float64 `json:"datapoints"`
+}
+
+// frame returns a Grafana table response holding rows, converting each value to its column type
+func (schema tableSchema) frame(refID string, rows [][]string) tableFrame {
+	frame := tableFrame{
+		RefID:   refID,
+		Columns: make([]frameColumn, 0, len(schema)),
+		Rows:    make([][]interface{}, 0, len(rows)),
+		Type:    targetTable,
+	}
+	for _, column := range schema {
+		frame.Columns = append(frame.Columns, frameColumn{Text: column.Text, Type: column.Type})
+	}
+	for _, row := range rows {
+		values := make([]interface{}, 0, len(schema))
+		for i, column := range schema {
+			values = append(values, typedValue(column.Type, row[i]))
+		}
+		frame.Rows = append(frame.Rows, values)
+	}
+	return frame
+}
+
+// typedValue converts a raw column value to the JSON value Grafana expects for the column type.
+// Values that cannot be converted are returned as null.
+func typedValue(columnType, value string) interface{} {
+	switch columnType {
+	case columnNumber:
+		bytes, ok := parseBytes(value)
+		if !ok {
+			return nil
+		}
+		return bytes
+	case columnTime:
+		t, ok := parseTime(value)
+		if !ok {
+			return nil
+		}
+		return t.UnixMilli()
+	default:
+		return value
+	}
+}
+
+// parseBytes parses a Kubernetes quantity such as "16Gi" into bytes
+func parseBytes(value string) (int64, bool) {
+	quantity, err := resource.ParseQuantity(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return quantity.Value(), true
+}
+
+// parseTime parses a volume creation time
+func parseTime(value string) (time.Time, bool) {
+	t, err := time.Parse(createdTimeLayout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,163 @@
+/*
+ Copyright (c) 2026 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package service_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dell/karavi-topology/internal/k8s"
+	"github.com/dell/karavi-topology/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testFrame struct {
+	RefID   string `json:"refId"`
+	Columns []struct {
+		Text string `json:"text"`
+		Type string `json:"type"`
+	} `json:"columns"`
+	Rows       [][]interface{} `json:"rows"`
+	Type       string          `json:"type"`
+	Target     string          `json:"target"`
+	Datapoints [][]float64     `json:"datapoints"`
+}
+
+func queryFrames(t *testing.T, body string) (int, []testFrame) {
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(1).Return(testVolumes(), nil)
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	status, resBody := post(t, ctx.server.URL+"/query", body)
+	var frames []testFrame
+	if status == http.StatusOK {
+		assert.Nil(t, json.Unmarshal(resBody, &frames))
+	}
+	return status, frames
+}
+
+func TestQueryTableResponse(t *testing.T) {
+	status, frames := queryFrames(t, `{"targets": [{"target": "{}", "refId": "A", "type": "table"}]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, len(frames))
+
+	frame := frames[0]
+	assert.Equal(t, "A", frame.RefID)
+	assert.Equal(t, "table", frame.Type)
+	assert.Equal(t, len(allColumns), len(frame.Columns))
+	assert.Equal(t, "Namespace", frame.Columns[0].Text)
+	assert.Equal(t, "string", frame.Columns[0].Type)
+	assert.Equal(t, "Created", frame.Columns[5].Text)
+	assert.Equal(t, "time", frame.Columns[5].Type)
+	assert.Equal(t, "Provisioned Size", frame.Columns[6].Text)
+	assert.Equal(t, "number", frame.Columns[6].Type)
+
+	assert.Equal(t, 2, len(frame.Rows))
+	assert.Equal(t, "ns-1", frame.Rows[0][0])
+	assert.Equal(t, float64(1595966400000), frame.Rows[0][5])
+	assert.Equal(t, float64(8*1024*1024*1024), frame.Rows[0][6])
+}
+
+func TestQueryTimeSeriesResponse(t *testing.T) {
+	status, frames := queryFrames(t, `{"targets": [{"target": "{}", "refId": "B", "type": "timeserie"}]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, len(frames))
+	assert.Equal(t, "B", frames[0].RefID)
+	assert.Equal(t, "volumes", frames[0].Target)
+	assert.Equal(t, 1, len(frames[0].Datapoints))
+	assert.Equal(t, float64(2), frames[0].Datapoints[0][0])
+}
+
+func TestQueryUntypedTargetResponse(t *testing.T) {
+	status, frames := queryFrames(t, `{"targets": [{"target": "{}", "refId": "C"}]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, len(frames))
+	assert.Equal(t, "C", frames[0].RefID)
+	assert.Equal(t, "table", frames[0].Type)
+	assert.Equal(t, 2, len(frames[0].Rows))
+}
+
+func TestQueryPerTargetResponse(t *testing.T) {
+	body := `{"targets": [
+		{"target": "{\"Namespace\": \"ns-1\"}", "refId": "A", "type": "table"},
+		{"target": "{\"Namespace\": \"ns-2\"}", "refId": "B", "type": "table"},
+		{"target": "{\"Namespace\": \"ns-3\"}", "refId": "C", "type": "table", "hide": true}
+	]}`
+	status, frames := queryFrames(t, body)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 2, len(frames))
+
+	assert.Equal(t, "A", frames[0].RefID)
+	assert.Equal(t, 1, len(frames[0].Rows))
+	assert.Equal(t, "ns-1", frames[0].Rows[0][0])
+
+	assert.Equal(t, "B", frames[1].RefID)
+	assert.Equal(t, 1, len(frames[1].Rows))
+	assert.Equal(t, "ns-2", frames[1].Rows[0][0])
+}
+
+func TestQueryAllTargetsHidden(t *testing.T) {
+	status, frames := queryFrames(t, `{"targets": [{"target": "{}", "refId": "A", "type": "table", "hide": true}]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 0, len(frames))
+}
+
+func TestQueryUnboundClaims(t *testing.T) {
+	created := time.Now().Add(-90 * time.Minute).Round(0)
+	volumes := append(testVolumes(), k8s.VolumeInfo{
+		Namespace:              "ns-1",
+		VolumeClaimName:        "pvc-3",
+		PersistentVolumeStatus: "Unbound",
+		StorageClass:           "powerstore",
+		Driver:                 "csi-powerstore.dellemc.com",
+		ProvisionedSize:        "8Gi",
+		CreatedTime:            created.String(),
+		Message:                "failed to provision volume: pool is full",
+	})
+
+	ctrl := gomock.NewController(t)
+	volumeFinder := mocks.NewMockVolumeInfoGetter(ctrl)
+	volumeFinder.EXPECT().GetPersistentVolumes(gomock.Any()).Times(2).Return(volumes, nil)
+
+	ctx, teardown := setup(volumeFinder)
+	defer teardown()
+
+	status, body := get(t, ctx.server.URL+"/api/v1/volumes?status=Unbound&fields=persistent_volume_claim,persistent_volume,age,message")
+	assert.Equal(t, http.StatusOK, status)
+	var list testList
+	assert.Nil(t, json.Unmarshal(body, &list))
+	assert.Equal(t, []map[string]interface{}{{
+		"persistent_volume_claim": "pvc-3",
+		"persistent_volume":       "",
+		"age":                     "90m",
+		"message":                 "failed to provision volume: pool is full",
+	}}, list.Items)
+
+	// unbound claims are not counted by aggregations
+	status, body = post(t, ctx.server.URL+"/query", `{"targets": [{"target": "{\"$groupBy\": \"Namespace\", \"$aggregate\": [\"count\", \"sum\"]}", "refId": "A", "type": "table"}]}`)
+	assert.Equal(t, http.StatusOK, status)
+	var frames []testFrame
+	assert.Nil(t, json.Unmarshal(body, &frames))
+	assert.Equal(t, [][]interface{}{{"ns-1", float64(1), float64(8 * gib)}, {"ns-2", float64(1), float64(16 * gib)}}, frames[0].Rows)
+}